@@ -3,14 +3,52 @@ package codewars
 type ActionType int
 
 const (
-	Action_None ActionType = iota
-	Action_Clear_And_Select
-	Action_Add_To_Selection
-	Action_Deselect
-	Action_Assign
-	Action_Dismiss
-	Action_Disband
-	Action_Move
-	Action_Rotate
-	Action_Setup_Vehicle_Production
+	Action_None                     ActionType = 0
+	Action_Clear_And_Select         ActionType = 1
+	Action_Add_To_Selection         ActionType = 2
+	Action_Deselect                 ActionType = 3
+	Action_Assign                   ActionType = 4
+	Action_Dismiss                  ActionType = 5
+	Action_Disband                  ActionType = 6
+	Action_Move                     ActionType = 7
+	Action_Rotate                   ActionType = 8
+	Action_Scale                    ActionType = 9
+	Action_Setup_Vehicle_Production ActionType = 10
+	Action_Tactical_Nuclear_Strike  ActionType = 11
 )
+
+var actionTypeInfo = enumInfo{"ActionType", []string{
+	"None",
+	"Clear_And_Select",
+	"Add_To_Selection",
+	"Deselect",
+	"Assign",
+	"Dismiss",
+	"Disband",
+	"Move",
+	"Rotate",
+	"Scale",
+	"Setup_Vehicle_Production",
+	"Tactical_Nuclear_Strike",
+}, ""}
+
+func (t ActionType) String() string { return actionTypeInfo.text(int(t)) }
+
+func (t ActionType) MarshalText() ([]byte, error) { return actionTypeInfo.marshal(int(t)) }
+
+func (t *ActionType) UnmarshalText(text []byte) error {
+	v, err := actionTypeInfo.unmarshal(text)
+	if err != nil {
+		return err
+	}
+	*t = ActionType(v)
+	return nil
+}
+
+// Encode returns the wire code of t.
+func (t ActionType) Encode() (byte, error) { return actionTypeInfo.encode(int(t)) }
+
+func DecodeActionType(b byte) (ActionType, error) {
+	v, err := actionTypeInfo.decode(b)
+	return ActionType(v), err
+}
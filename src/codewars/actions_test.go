@@ -0,0 +1,70 @@
+package codewars
+
+import (
+	"testing"
+)
+
+// The codes of the official protocol table.
+var actionCodes = []struct {
+	action ActionType
+	code   int
+}{
+	{Action_None, 0},
+	{Action_Clear_And_Select, 1},
+	{Action_Add_To_Selection, 2},
+	{Action_Deselect, 3},
+	{Action_Assign, 4},
+	{Action_Dismiss, 5},
+	{Action_Disband, 6},
+	{Action_Move, 7},
+	{Action_Rotate, 8},
+	{Action_Scale, 9},
+	{Action_Setup_Vehicle_Production, 10},
+	{Action_Tactical_Nuclear_Strike, 11},
+}
+
+func TestActionTypeCodes(t *testing.T) {
+	for _, c := range actionCodes {
+		if int(c.action) != c.code {
+			t.Errorf("%v = %d, want %d", c.action, int(c.action), c.code)
+		}
+		b, err := c.action.Encode()
+		if err != nil || int(b) != c.code {
+			t.Errorf("%v.Encode() = %d, %v, want %d", c.action, b, err, c.code)
+		}
+	}
+}
+
+func TestActionTypeRoundTrip(t *testing.T) {
+	for i := 0; i < 256; i++ {
+		b := byte(i)
+		a, err := DecodeActionType(b)
+		valid := i < len(actionCodes)
+		switch {
+		case valid && err != nil:
+			t.Errorf("DecodeActionType(%d): %v", i, err)
+		case !valid && err == nil:
+			t.Errorf("DecodeActionType(%d) = %v, want an error", i, a)
+		case valid:
+			if e, err := a.Encode(); err != nil || e != b {
+				t.Errorf("%v.Encode() = %d, %v, want %d", a, e, err, b)
+			}
+		}
+	}
+
+	// ActionType has no null value: Action_None is the empty action, and
+	// the null code is rejected both ways.
+	if a, err := DecodeActionType(nullCode); err == nil {
+		t.Errorf("DecodeActionType(0xFF) = %v, want an error", a)
+	}
+	if b, err := ActionType(-1).Encode(); err == nil {
+		t.Errorf("ActionType(-1).Encode() = %d, want an error", b)
+	}
+	// Enums with a null value map it to 0xFF and back.
+	if b, err := Vehicle_Unknown.Encode(); err != nil || b != nullCode {
+		t.Errorf("Vehicle_Unknown.Encode() = %d, %v, want 0xFF", b, err)
+	}
+	if v, err := DecodeVehicleType(nullCode); err != nil || v != Vehicle_Unknown {
+		t.Errorf("DecodeVehicleType(0xFF) = %v, %v, want Unknown", v, err)
+	}
+}
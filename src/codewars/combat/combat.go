@@ -0,0 +1,188 @@
+// Package combat estimates how a fight between two sets of vehicles ends.
+package combat
+
+import (
+	. "codewars"
+)
+
+// SideOutcome is how one side of a fight ends up.
+type SideOutcome struct {
+	Count          int
+	Survivors      int
+	Durability     int
+	DurabilityLost int
+	// tick of the fight at which the last vehicle of the side was destroyed,
+	// -1 if some survived
+	EliminatedAt int
+}
+
+type Outcome struct {
+	// ticks simulated, fewer than asked when the fight was over earlier
+	Ticks         int
+	First, Second SideOutcome
+}
+
+// Balance compares the shares of durability the sides kept, from 1 when
+// only the first side has any left to -1 when only the second has.
+func (o Outcome) Balance() float64 {
+	return share(o.First) - share(o.Second)
+}
+
+func share(s SideOutcome) float64 {
+	total := s.Durability + s.DurabilityLost
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Durability) / float64(total)
+}
+
+// FirstWins reports whether the second side is eliminated while the first is
+// not, or, if the fight is still on, whether the first side is ahead.
+func (o Outcome) FirstWins() bool {
+	if o.First.Survivors == 0 || o.Second.Survivors == 0 {
+		return o.Second.Survivors == 0 && o.First.Survivors > 0
+	}
+	return o.Balance() > 0
+}
+
+type fighter struct {
+	t          VehicleType
+	durability int
+	cooldown   int
+	reload     int
+}
+
+// Estimate plays the fight of first against second for up to ticks ticks
+// with a discrete model of the game's combat. Both sides are assumed to be
+// engaged all along: any vehicle that can hurt an enemy kind at all is in
+// range of every enemy. Every tick, each vehicle that is ready fires at the
+// enemy it hurts most, preferring the one closest to being destroyed so that
+// fire gets focused, and all shots land together. The current Durability and
+// RemainingAttackCooldownTicks of the vehicles are the starting point, the
+// damage, defence and cooldowns are those of game. Repairs, movement and
+// terrain are ignored.
+func Estimate(game *Game, first, second []*Vehicle, ticks int) Outcome {
+	damage := NewDamageMatrix(game)
+	for _, a := range VehicleTypes {
+		for _, t := range VehicleTypes {
+			if game.Stats(a).AttackRange(game.Stats(t).Aerial) <= 0 {
+				damage[a][t] = 0
+			}
+		}
+	}
+	sides := [2][]*fighter{fighters(game, first), fighters(game, second)}
+	o := Outcome{First: sideOutcome(sides[0]), Second: sideOutcome(sides[1])}
+	initial := [2]int{o.First.Durability, o.Second.Durability}
+	eliminated := [2]int{-1, -1}
+
+	for o.Ticks < ticks && alive(sides[0]) > 0 && alive(sides[1]) > 0 && canFight(damage, sides) {
+		var hits [2][]int
+		for s := range sides {
+			hits[1-s] = make([]int, len(sides[1-s]))
+		}
+		for s, side := range sides {
+			enemies := sides[1-s]
+			for _, f := range side {
+				if f.durability <= 0 || f.cooldown > 0 {
+					continue
+				}
+				target, best := -1, 0
+				for i, e := range enemies {
+					left := e.durability - hits[1-s][i]
+					if left <= 0 {
+						continue
+					}
+					d := damage.Damage(f.t, e.t)
+					if d > best || d == best && d > 0 && left < enemies[target].durability-hits[1-s][target] {
+						target, best = i, d
+					}
+				}
+				if target >= 0 {
+					hits[1-s][target] += best
+					f.cooldown = f.reload
+				}
+			}
+		}
+		for s := range sides {
+			for i, e := range sides[s] {
+				e.durability -= hits[s][i]
+				// Like the game, count down after the shots of the tick,
+				// so a vehicle fires every reload ticks.
+				if e.cooldown > 0 {
+					e.cooldown--
+				}
+			}
+			if eliminated[s] < 0 && alive(sides[s]) == 0 {
+				eliminated[s] = o.Ticks
+			}
+		}
+		o.Ticks++
+	}
+
+	for s, out := range []*SideOutcome{&o.First, &o.Second} {
+		*out = sideOutcome(sides[s])
+		out.DurabilityLost = initial[s] - out.Durability
+		out.EliminatedAt = eliminated[s]
+	}
+	return o
+}
+
+func fighters(game *Game, vehicles []*Vehicle) []*fighter {
+	r := make([]*fighter, 0, len(vehicles))
+	for _, v := range vehicles {
+		if v == nil || v.Durability <= 0 {
+			continue
+		}
+		r = append(r, &fighter{
+			t:          v.VehicleType,
+			durability: v.Durability,
+			cooldown:   v.RemainingAttackCooldownTicks,
+			reload:     game.Stats(v.VehicleType).AttackCooldownTicks,
+		})
+	}
+	return r
+}
+
+func sideOutcome(side []*fighter) SideOutcome {
+	s := SideOutcome{Count: len(side), EliminatedAt: -1}
+	for _, f := range side {
+		if f.durability > 0 {
+			s.Survivors++
+			s.Durability += f.durability
+		}
+	}
+	return s
+}
+
+func alive(side []*fighter) int {
+	n := 0
+	for _, f := range side {
+		if f.durability > 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// canFight reports whether some living vehicle can still hurt an enemy.
+func canFight(damage *DamageMatrix, sides [2][]*fighter) bool {
+	var types [2]map[VehicleType]bool
+	for s, side := range sides {
+		types[s] = make(map[VehicleType]bool)
+		for _, f := range side {
+			if f.durability > 0 {
+				types[s][f.t] = true
+			}
+		}
+	}
+	for s := range sides {
+		for a := range types[s] {
+			for t := range types[1-s] {
+				if damage.Damage(a, t) > 0 {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
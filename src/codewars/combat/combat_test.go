@@ -0,0 +1,79 @@
+package combat
+
+import (
+	. "codewars"
+	"testing"
+)
+
+func formation(game *Game, t VehicleType, n int) []*Vehicle {
+	stats := game.Stats(t)
+	r := make([]*Vehicle, n)
+	for i := range r {
+		r[i] = &Vehicle{
+			VehicleType:   t,
+			Aerial:        stats.Aerial,
+			Durability:    stats.Durability,
+			MaxDurability: stats.Durability,
+		}
+	}
+	return r
+}
+
+func TestClassicMatchups(t *testing.T) {
+	game := NewGame()
+	for _, c := range []struct {
+		winner, loser VehicleType
+	}{
+		{Vehicle_Fighter, Vehicle_Helicopter},
+		{Vehicle_Ifv, Vehicle_Helicopter},
+		{Vehicle_Helicopter, Vehicle_Tank},
+		{Vehicle_Tank, Vehicle_Ifv},
+	} {
+		o := Estimate(game, formation(game, c.winner, 100), formation(game, c.loser, 100), game.TickCount)
+		if !o.FirstWins() || o.Second.Survivors != 0 || o.First.Survivors == 0 {
+			t.Errorf("%v vs %v: %+v, want the %v to win", c.winner, c.loser, o, c.winner)
+		}
+		o = Estimate(game, formation(game, c.loser, 100), formation(game, c.winner, 100), game.TickCount)
+		if o.FirstWins() || o.First.Survivors != 0 {
+			t.Errorf("%v vs %v: %+v, want the %v to lose", c.loser, c.winner, o, c.loser)
+		}
+	}
+}
+
+func TestMirrorMatchIsEven(t *testing.T) {
+	game := NewGame()
+	o := Estimate(game, formation(game, Vehicle_Tank, 50), formation(game, Vehicle_Tank, 50), game.TickCount)
+	if o.First != o.Second || o.Balance() != 0 {
+		t.Errorf("tanks vs tanks: %+v", o)
+	}
+}
+
+func TestNoDamageNoFight(t *testing.T) {
+	game := NewGame()
+	o := Estimate(game, formation(game, Vehicle_Fighter, 10), formation(game, Vehicle_Tank, 10), game.TickCount)
+	if o.Ticks != 0 || o.First.DurabilityLost != 0 || o.Second.DurabilityLost != 0 {
+		t.Errorf("fighters vs tanks: %+v, want no fight", o)
+	}
+}
+
+func TestFiringPeriod(t *testing.T) {
+	game := NewGame()
+	// Two shots of a tank destroy an ARRV, on the first tick and once the
+	// cooldown is over.
+	o := Estimate(game, formation(game, Vehicle_Tank, 1), formation(game, Vehicle_Arrv, 1), game.TickCount)
+	if o.Second.EliminatedAt != game.TankAttackCooldownTicks {
+		t.Errorf("ARRV destroyed on tick %d, want %d", o.Second.EliminatedAt, game.TankAttackCooldownTicks)
+	}
+
+	tank := formation(game, Vehicle_Tank, 1)
+	tank[0].RemainingAttackCooldownTicks = 10
+	o = Estimate(game, tank, formation(game, Vehicle_Arrv, 1), game.TickCount)
+	if want := 10 + game.TankAttackCooldownTicks; o.Second.EliminatedAt != want {
+		t.Errorf("ARRV destroyed on tick %d, want %d", o.Second.EliminatedAt, want)
+	}
+
+	o = Estimate(game, formation(game, Vehicle_Tank, 1), formation(game, Vehicle_Arrv, 1), game.TankAttackCooldownTicks)
+	if o.Second.Survivors != 1 || o.Second.DurabilityLost != game.TankGroundDamage-game.ArrvGroundDefence {
+		t.Errorf("after %d ticks: %+v, want one shot", game.TankAttackCooldownTicks, o)
+	}
+}
@@ -0,0 +1,135 @@
+package command
+
+import (
+	. "codewars"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// GroupStats aggregates the vehicles of a group.
+type GroupStats struct {
+	Count            int
+	CenterX, CenterY float64
+	Bounds           Rect
+	// MaxSpeed of the slowest member, before terrain and weather factors
+	MinSpeed      float64
+	Durability    int
+	MaxDurability int
+}
+
+// GroupRegistry keeps track of which of our vehicles are in which group.
+// Call Update with every world to take the membership the server reports,
+// then Apply with our move to predict its effect before the next update.
+type GroupRegistry struct {
+	game    *Game
+	members map[int]map[int64]bool
+}
+
+func NewGroupRegistry(game *Game) *GroupRegistry {
+	return &GroupRegistry{game: game, members: make(map[int]map[int64]bool)}
+}
+
+func (r *GroupRegistry) Update(world *World) {
+	r.members = make(map[int]map[int64]bool)
+	for _, v := range world.GetMyVehicles() {
+		for _, g := range v.Groups {
+			r.add(g, v.Id)
+		}
+	}
+}
+
+// Apply predicts the effect of move, made on world while selection holds the
+// selected vehicles, on the groups. It returns a *MoveError when the group of
+// the move is out of 1..Game.MaxUnitGroup, in which case the server ignores it.
+func (r *GroupRegistry) Apply(world *World, move *Move, selection *SelectionTracker) error {
+	switch move.Action {
+	case Action_Assign, Action_Dismiss, Action_Disband:
+	case Action_Clear_And_Select, Action_Add_To_Selection, Action_Deselect:
+		if move.Group > r.game.MaxUnitGroup {
+			return r.outOfRange(move)
+		}
+		return nil
+	default:
+		return nil
+	}
+	if move.Group < 1 || move.Group > r.game.MaxUnitGroup {
+		return r.outOfRange(move)
+	}
+
+	switch move.Action {
+	case Action_Assign:
+		for _, id := range selection.Selected() {
+			if world.GetVehicleById(id) != nil {
+				r.add(move.Group, id)
+			}
+		}
+	case Action_Dismiss:
+		for _, id := range selection.Selected() {
+			delete(r.members[move.Group], id)
+		}
+	case Action_Disband:
+		delete(r.members, move.Group)
+	}
+	return nil
+}
+
+func (r *GroupRegistry) outOfRange(move *Move) error {
+	return &MoveError{Action: move.Action, Reason: fmt.Sprintf("group %d is not in 1..%d", move.Group, r.game.MaxUnitGroup)}
+}
+
+func (r *GroupRegistry) add(group int, id int64) {
+	if r.members[group] == nil {
+		r.members[group] = make(map[int64]bool)
+	}
+	r.members[group][id] = true
+}
+
+// Groups returns the numbers of the groups that have members, in order.
+func (r *GroupRegistry) Groups() []int {
+	var groups []int
+	for g, m := range r.members {
+		if len(m) > 0 {
+			groups = append(groups, g)
+		}
+	}
+	sort.Ints(groups)
+	return groups
+}
+
+// Members returns the vehicles of group still alive in world, ordered by id.
+func (r *GroupRegistry) Members(world *World, group int) []*Vehicle {
+	var vehicles []*Vehicle
+	for id := range r.members[group] {
+		if v := world.GetVehicleById(id); v != nil {
+			vehicles = append(vehicles, v)
+		}
+	}
+	sort.Slice(vehicles, func(i, j int) bool { return vehicles[i].Id < vehicles[j].Id })
+	return vehicles
+}
+
+// Stats aggregates the members of group. Count is zero for an empty group.
+func (r *GroupRegistry) Stats(world *World, group int) GroupStats {
+	vehicles := r.Members(world, group)
+	s := GroupStats{Count: len(vehicles)}
+	if len(vehicles) == 0 {
+		return s
+	}
+	s.Bounds = Rect{Left: math.Inf(1), Top: math.Inf(1), Right: math.Inf(-1), Bottom: math.Inf(-1)}
+	s.MinSpeed = math.Inf(1)
+	for _, v := range vehicles {
+		s.CenterX += v.X
+		s.CenterY += v.Y
+		s.Bounds.Left = math.Min(s.Bounds.Left, v.X)
+		s.Bounds.Top = math.Min(s.Bounds.Top, v.Y)
+		s.Bounds.Right = math.Max(s.Bounds.Right, v.X)
+		s.Bounds.Bottom = math.Max(s.Bounds.Bottom, v.Y)
+		s.MinSpeed = math.Min(s.MinSpeed, v.MaxSpeed)
+		s.Durability += v.Durability
+		s.MaxDurability += v.MaxDurability
+	}
+	s.CenterX /= float64(len(vehicles))
+	s.CenterY /= float64(len(vehicles))
+	return s
+}
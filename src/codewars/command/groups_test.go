@@ -0,0 +1,127 @@
+package command
+
+import (
+	. "codewars"
+	"reflect"
+	"testing"
+)
+
+func ids(vehicles []*Vehicle) []int64 {
+	r := make([]int64, len(vehicles))
+	for i, v := range vehicles {
+		r[i] = v.Id
+	}
+	return r
+}
+
+func TestGroupRegistryApply(t *testing.T) {
+	world := newWorld(0,
+		vehicle(1, 1, Vehicle_Tank, 100, 100),
+		vehicle(2, 1, Vehicle_Tank, 200, 300),
+		vehicle(3, 1, Vehicle_Ifv, 400, 400),
+	)
+	registry := NewGroupRegistry(NewGame())
+	registry.Update(world)
+	selection := NewSelectionTracker()
+	do := func(move *Move) error {
+		selection.Apply(world, move)
+		return registry.Apply(world, move, selection)
+	}
+
+	do(new(Move).ClearAndSelect(Rect{Right: 1024, Bottom: 1024}, Vehicle_Tank))
+	if err := do(new(Move).Assign(4)); err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(registry.Members(world, 4)); !reflect.DeepEqual(got, []int64{1, 2}) {
+		t.Fatalf("group 4 after Assign = %v, want [1 2]", got)
+	}
+
+	do(new(Move).ClearAndSelect(Rect{Right: 1024, Bottom: 1024}, Vehicle_Unknown))
+	do(new(Move).Assign(5))
+	do(new(Move).ClearAndSelect(Rect{Left: 150, Top: 250, Right: 250, Bottom: 350}, Vehicle_Unknown))
+	if err := do(new(Move).Dismiss(4)); err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(registry.Members(world, 4)); !reflect.DeepEqual(got, []int64{1}) {
+		t.Fatalf("group 4 after Dismiss = %v, want [1]", got)
+	}
+	if got := ids(registry.Members(world, 5)); !reflect.DeepEqual(got, []int64{1, 2, 3}) {
+		t.Fatalf("group 5 = %v, want [1 2 3]", got)
+	}
+
+	if err := do(new(Move).Disband(5)); err != nil {
+		t.Fatal(err)
+	}
+	if got := registry.Members(world, 5); len(got) != 0 {
+		t.Fatalf("group 5 after Disband = %v", ids(got))
+	}
+	if got := registry.Groups(); !reflect.DeepEqual(got, []int{4}) {
+		t.Errorf("Groups() = %v, want [4]", got)
+	}
+
+	for _, move := range []*Move{new(Move).Assign(0), new(Move).Dismiss(101), new(Move).SelectGroup(101)} {
+		if _, ok := do(move).(*MoveError); !ok {
+			t.Errorf("%v of group %d accepted", move.Action, move.Group)
+		}
+	}
+	if got := registry.Groups(); !reflect.DeepEqual(got, []int{4}) {
+		t.Errorf("Groups() after ignored moves = %v, want [4]", got)
+	}
+}
+
+func TestGroupRegistryUpdate(t *testing.T) {
+	world := newWorld(0,
+		vehicle(1, 1, Vehicle_Tank, 100, 100),
+		vehicle(2, 1, Vehicle_Tank, 200, 300),
+		vehicle(3, 2, Vehicle_Tank, 300, 300),
+	)
+	world.VehicleById[1].Groups = []int{1, 2}
+	world.VehicleById[2].Groups = []int{2}
+	// Groups of the opponent are not ours.
+	world.VehicleById[3].Groups = []int{3}
+	registry := NewGroupRegistry(NewGame())
+	registry.Update(world)
+	if got := registry.Groups(); !reflect.DeepEqual(got, []int{1, 2}) {
+		t.Fatalf("Groups() = %v, want [1 2]", got)
+	}
+
+	// Tank 1 is destroyed: it is gone from its groups at once, and from the
+	// registry on the next update.
+	delete(world.VehicleById, 1)
+	if got := ids(registry.Members(world, 2)); !reflect.DeepEqual(got, []int64{2}) {
+		t.Errorf("group 2 after the death = %v, want [2]", got)
+	}
+	if s := registry.Stats(world, 1); s.Count != 0 {
+		t.Errorf("group 1 after the death has %d members", s.Count)
+	}
+	registry.Update(world)
+	if got := registry.Groups(); !reflect.DeepEqual(got, []int{2}) {
+		t.Errorf("Groups() after the update = %v, want [2]", got)
+	}
+}
+
+func TestGroupStats(t *testing.T) {
+	fast := vehicle(1, 1, Vehicle_Tank, 100, 100)
+	fast.MaxSpeed, fast.MaxDurability, fast.Groups = 0.4, 100, []int{7}
+	slow := vehicle(2, 1, Vehicle_Arrv, 200, 300)
+	slow.MaxSpeed, slow.Durability, slow.MaxDurability, slow.Groups = 0.3, 50, 100, []int{7}
+	world := newWorld(0, fast, slow)
+	registry := NewGroupRegistry(NewGame())
+	registry.Update(world)
+
+	want := GroupStats{
+		Count:         2,
+		CenterX:       150,
+		CenterY:       200,
+		Bounds:        Rect{Left: 100, Top: 100, Right: 200, Bottom: 300},
+		MinSpeed:      0.3,
+		Durability:    150,
+		MaxDurability: 200,
+	}
+	if got := registry.Stats(world, 7); got != want {
+		t.Errorf("Stats(7) = %+v, want %+v", got, want)
+	}
+	if got := registry.Stats(world, 8); got != (GroupStats{}) {
+		t.Errorf("Stats of an empty group = %+v", got)
+	}
+}
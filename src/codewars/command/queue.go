@@ -0,0 +1,149 @@
+package command
+
+import (
+	. "codewars"
+)
+
+// Step fills move with one action of an order, given the world of the tick
+// it is made on and the selection of the order, which is current.
+type Step func(world *World, sel Selection, move *Move)
+
+// Do returns a step making a fixed action, e.g. Do(*new(Move).Assign(3)).
+func Do(action Move) Step {
+	return func(world *World, sel Selection, move *Move) {
+		*move = action
+	}
+}
+
+// MoveTo returns a step moving the selection so that its center ends up at
+// (x, y), no faster than maxSpeed unless it is zero.
+func MoveTo(x, y, maxSpeed float64) Step {
+	return func(world *World, sel Selection, move *Move) {
+		vehicles := sel.Vehicles(world)
+		if len(vehicles) == 0 {
+			move.None()
+			return
+		}
+		cx, cy := 0.0, 0.0
+		for _, v := range vehicles {
+			cx += v.X
+			cy += v.Y
+		}
+		n := float64(len(vehicles))
+		move.MoveBy(x-cx/n, y-cy/n, maxSpeed)
+	}
+}
+
+// Order is a sequence of steps applied to a selection.
+type Order struct {
+	Selection Selection
+	Priority  int
+
+	steps    []Step
+	next     int
+	seq      int
+	canceled bool
+}
+
+// Cancel drops the steps of the order that have not been made yet.
+func (o *Order) Cancel() {
+	o.canceled = true
+}
+
+// Done reports whether the order has made all its steps or was canceled.
+func (o *Order) Done() bool {
+	return o.canceled || o.next >= len(o.steps)
+}
+
+// Budget tells how many actions a player may still make, e.g. the
+// *runner.ActionBudget the runner gives a BudgetedStrategy.
+type Budget interface {
+	Remaining() int
+}
+
+// Queue turns orders into one Move per tick. It runs the pending order with
+// the highest priority, the oldest first on a tie, and selects the vehicles
+// of an order only when the selection it last made is not the same one. A
+// higher priority order preempts a running one, which resumes where it
+// stopped. Orders whose selection picks none of our vehicles are passed over
+// until it does.
+type Queue struct {
+	orders  []*Order
+	seq     int
+	current *Selection
+	budget  Budget
+}
+
+func NewQueue() *Queue {
+	return &Queue{}
+}
+
+// Add queues steps to be made with sel selected.
+func (q *Queue) Add(priority int, sel Selection, steps ...Step) *Order {
+	q.seq++
+	o := &Order{Selection: sel, Priority: priority, steps: steps, seq: q.seq}
+	q.orders = append(q.orders, o)
+	return o
+}
+
+// Pending returns the orders that are not done yet.
+func (q *Queue) Pending() []*Order {
+	q.prune()
+	return append([]*Order(nil), q.orders...)
+}
+
+// SetActionBudget makes the queue wait while budget has no action left.
+func (q *Queue) SetActionBudget(budget Budget) {
+	q.budget = budget
+}
+
+// Forget makes the queue reselect before the next step, e.g. after the
+// strategy changed the selection with a move of its own.
+func (q *Queue) Forget() {
+	q.current = nil
+}
+
+// Next fills move with the next action to make, or leaves it untouched when
+// there is nothing to do or me cannot act on this tick.
+func (q *Queue) Next(me *Player, world *World, move *Move) {
+	if me.RemainingActionCooldownTicks > 0 || q.budget != nil && q.budget.Remaining() <= 0 {
+		return
+	}
+	q.prune()
+	var o *Order
+	for _, c := range q.orders {
+		if o != nil && (c.Priority < o.Priority || c.Priority == o.Priority && c.seq > o.seq) {
+			continue
+		}
+		if len(c.Selection.Vehicles(world)) > 0 {
+			o = c
+		}
+	}
+	if o == nil {
+		return
+	}
+
+	if q.current == nil || *q.current != o.Selection {
+		o.Selection.Select(move)
+		sel := o.Selection
+		q.current = &sel
+		return
+	}
+
+	o.steps[o.next](world, o.Selection, move)
+	o.next++
+	switch move.Action {
+	case Action_Clear_And_Select, Action_Add_To_Selection, Action_Deselect:
+		q.current = nil
+	}
+}
+
+func (q *Queue) prune() {
+	orders := q.orders[:0]
+	for _, o := range q.orders {
+		if !o.Done() {
+			orders = append(orders, o)
+		}
+	}
+	q.orders = orders
+}
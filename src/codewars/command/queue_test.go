@@ -0,0 +1,173 @@
+package command
+
+import (
+	. "codewars"
+	"codewars/runner"
+	"testing"
+)
+
+var (
+	me       = &Player{Id: 1, Me: true}
+	opponent = &Player{Id: 2}
+)
+
+func vehicle(id, player int64, t VehicleType, x, y float64) *Vehicle {
+	return &Vehicle{
+		CircularUnit: CircularUnit{Unit: Unit{Id: id, X: x, Y: y}, Radius: 2},
+		PlayerId:     player,
+		Durability:   100,
+		VehicleType:  t,
+	}
+}
+
+func newWorld(tick int, vehicles ...*Vehicle) *World {
+	w := &World{
+		TickIndex:   tick,
+		Width:       1024,
+		Height:      1024,
+		Players:     []*Player{me, opponent},
+		VehicleById: make(map[int64]*Vehicle),
+	}
+	for _, v := range vehicles {
+		w.VehicleById[v.Id] = v
+	}
+	return w
+}
+
+func next(q *Queue, world *World) *Move {
+	move := NewMove()
+	q.Next(me, world, move)
+	return move
+}
+
+func TestQueueSelectsOnce(t *testing.T) {
+	world := newWorld(0, vehicle(1, 1, Vehicle_Tank, 100, 100), vehicle(2, 1, Vehicle_Tank, 120, 100))
+	q := NewQueue()
+	tanks := Everything(world, Vehicle_Tank)
+	q.Add(0, tanks, MoveTo(310, 300, 0.2))
+	q.Add(0, tanks, Do(*new(Move).Assign(3)))
+
+	if m := next(q, world); m.Action != Action_Clear_And_Select || m.Vehicle_type != Vehicle_Tank {
+		t.Fatalf("first move = %+v, want the tanks selected", *m)
+	}
+	if m := next(q, world); m.Action != Action_Move || m.X != 200 || m.Y != 200 || m.Max_speed != 0.2 {
+		t.Fatalf("second move = %+v, want a move by (200, 200)", *m)
+	}
+	// The same selection is still current.
+	if m := next(q, world); m.Action != Action_Assign || m.Group != 3 {
+		t.Fatalf("third move = %+v, want an assignment to group 3", *m)
+	}
+	if m := next(q, world); m.Action != Action_None || len(q.Pending()) != 0 {
+		t.Fatalf("move with no order left = %+v, %d pending", *m, len(q.Pending()))
+	}
+}
+
+func TestQueueSkipsEmptySelection(t *testing.T) {
+	world := newWorld(0, vehicle(1, 1, Vehicle_Tank, 100, 100), vehicle(2, 2, Vehicle_Fighter, 500, 500))
+	q := NewQueue()
+	// Only the opponent has fighters.
+	fighters := q.Add(10, Everything(world, Vehicle_Fighter), MoveTo(0, 0, 0))
+	q.Add(0, Everything(world, Vehicle_Tank), MoveTo(0, 0, 0))
+
+	if m := next(q, world); m.Action != Action_Clear_And_Select || m.Vehicle_type != Vehicle_Tank {
+		t.Fatalf("first move = %+v, want the tanks selected", *m)
+	}
+	if fighters.Done() {
+		t.Fatal("order with an empty selection dropped")
+	}
+	next(q, world)
+
+	// Once a fighter of ours is there, its order runs.
+	world.VehicleById[3] = vehicle(3, 1, Vehicle_Fighter, 200, 200)
+	if m := next(q, world); m.Action != Action_Clear_And_Select || m.Vehicle_type != Vehicle_Fighter {
+		t.Fatalf("move = %+v, want the fighters selected", *m)
+	}
+}
+
+func TestQueuePreemption(t *testing.T) {
+	world := newWorld(0, vehicle(1, 1, Vehicle_Tank, 100, 100), vehicle(2, 1, Vehicle_Ifv, 300, 300))
+	q := NewQueue()
+	tanks, ifvs := Everything(world, Vehicle_Tank), Everything(world, Vehicle_Ifv)
+	low := q.Add(0, tanks, Do(*new(Move).Assign(1)), Do(*new(Move).Assign(2)))
+	next(q, world)
+	if m := next(q, world); m.Action != Action_Assign || m.Group != 1 {
+		t.Fatalf("move = %+v, want the first step of the tanks", *m)
+	}
+
+	q.Add(5, ifvs, Do(*new(Move).Assign(3)))
+	for i, want := range []struct {
+		action ActionType
+		t      VehicleType
+		group  int
+	}{
+		{Action_Clear_And_Select, Vehicle_Ifv, 0},
+		{Action_Assign, Vehicle_Unknown, 3},
+		// The tanks resume where they stopped, selected again.
+		{Action_Clear_And_Select, Vehicle_Tank, 0},
+		{Action_Assign, Vehicle_Unknown, 2},
+	} {
+		m := next(q, world)
+		if m.Action != want.action || m.Action == Action_Clear_And_Select && m.Vehicle_type != want.t || m.Group != want.group {
+			t.Fatalf("move %d = %+v, want %v of %v, group %d", i, *m, want.action, want.t, want.group)
+		}
+	}
+	if !low.Done() {
+		t.Error("preempted order not done")
+	}
+}
+
+func TestQueueCancel(t *testing.T) {
+	world := newWorld(0, vehicle(1, 1, Vehicle_Tank, 100, 100))
+	q := NewQueue()
+	tanks := Everything(world, Vehicle_Tank)
+	first := q.Add(0, tanks, Do(*new(Move).Assign(1)), Do(*new(Move).Assign(2)))
+	second := q.Add(0, tanks, Do(*new(Move).Assign(3)))
+	second.Cancel()
+
+	next(q, world)
+	next(q, world)
+	first.Cancel()
+	if m := next(q, world); m.Action != Action_None {
+		t.Errorf("move after canceling every order = %+v", *m)
+	}
+	if !first.Done() || !second.Done() || len(q.Pending()) != 0 {
+		t.Errorf("canceled orders still pending: %d", len(q.Pending()))
+	}
+}
+
+func TestQueueWaitsForActions(t *testing.T) {
+	g := NewGame()
+	g.BaseActionCount = 2
+	world := newWorld(0, vehicle(1, 1, Vehicle_Tank, 100, 100))
+	q := NewQueue()
+	budget := runner.NewActionBudget(g)
+	q.SetActionBudget(budget)
+	order := q.Add(0, Everything(world, Vehicle_Tank), Do(*new(Move).Assign(1)), Do(*new(Move).Assign(2)))
+
+	made := 0
+	for tick := 0; tick <= g.ActionDetectionInterval; tick++ {
+		world.TickIndex = tick
+		budget.Update(me, world)
+		m := next(q, world)
+		budget.Record(m)
+		if m.Action == Action_None {
+			continue
+		}
+		made++
+		if tick >= 2 && tick < g.ActionDetectionInterval {
+			t.Fatalf("tick %d: %v made with %d actions left", tick, m.Action, budget.Remaining())
+		}
+	}
+	if made != 3 || !order.Done() {
+		t.Errorf("%d actions made, order done: %v; want 3, true", made, order.Done())
+	}
+
+	// Nothing is made on cooldown either.
+	q.Add(0, Everything(world, Vehicle_Tank), Do(*new(Move).Assign(3)))
+	cooling := &Player{Id: 1, Me: true, RemainingActionCooldownTicks: 5}
+	move := NewMove()
+	q.Next(cooling, world, move)
+	if move.Action != Action_None {
+		t.Errorf("move on cooldown = %+v", *move)
+	}
+}
@@ -0,0 +1,60 @@
+package command
+
+import (
+	. "codewars"
+)
+
+// Selection describes a set of our vehicles: the members of Group if it is
+// set, otherwise those within Rect, in both cases only of Type unless it is
+// Vehicle_Unknown.
+type Selection struct {
+	Group int
+	Rect  Rect
+	Type  VehicleType
+}
+
+// Everything selects all our vehicles of type t in world.
+func Everything(world *World, t VehicleType) Selection {
+	return Selection{Rect: Rect{Right: world.Width, Bottom: world.Height}, Type: t}
+}
+
+func GroupSelection(group int) Selection {
+	return Selection{Group: group, Type: Vehicle_Unknown}
+}
+
+// Matches tells whether the server would select v, one of our vehicles.
+func (s Selection) Matches(v *Vehicle) bool {
+	if s.Type != Vehicle_Unknown && v.VehicleType != s.Type {
+		return false
+	}
+	if s.Group > 0 {
+		for _, g := range v.Groups {
+			if g == s.Group {
+				return true
+			}
+		}
+		return false
+	}
+	return v.X >= s.Rect.Left && v.X <= s.Rect.Right && v.Y >= s.Rect.Top && v.Y <= s.Rect.Bottom
+}
+
+// Vehicles returns our vehicles in world the selection picks.
+func (s Selection) Vehicles(world *World) []*Vehicle {
+	var r []*Vehicle
+	for _, v := range world.GetMyVehicles() {
+		if s.Matches(v) {
+			r = append(r, v)
+		}
+	}
+	return r
+}
+
+// Select makes move a Clear_And_Select of the selection.
+func (s Selection) Select(move *Move) *Move {
+	if s.Group > 0 {
+		move.SelectGroup(s.Group)
+		move.Vehicle_type = s.Type
+		return move
+	}
+	return move.ClearAndSelect(s.Rect, s.Type)
+}
@@ -0,0 +1,102 @@
+package command
+
+import (
+	. "codewars"
+	"fmt"
+	"sort"
+)
+
+// SelectionMismatch lists the vehicles whose Selected flag the server
+// reported differently from what SelectionTracker predicted, e.g. because a
+// move was ignored or a vehicle crossed the edge of a selection rectangle.
+type SelectionMismatch struct {
+	TickIndex int
+	// selected although not predicted
+	Unexpected []int64
+	// predicted but not selected
+	Missing []int64
+}
+
+func (m *SelectionMismatch) Error() string {
+	return fmt.Sprintf("codewars: tick %d: selection differs from prediction: %d unexpected, %d missing",
+		m.TickIndex, len(m.Unexpected), len(m.Missing))
+}
+
+// SelectionTracker predicts which of our vehicles are selected right after
+// our own moves, before the server reports it through Vehicle.Selected on
+// the next tick. Call Update with every world before moving and Apply with
+// the move made on it.
+type SelectionTracker struct {
+	selected  map[int64]bool
+	predicted bool
+}
+
+func NewSelectionTracker() *SelectionTracker {
+	return &SelectionTracker{selected: make(map[int64]bool)}
+}
+
+// Update replaces the prediction with the selection the server reports in
+// world and returns the differences if they disagree.
+func (t *SelectionTracker) Update(world *World) *SelectionMismatch {
+	m := &SelectionMismatch{TickIndex: world.TickIndex}
+	actual := make(map[int64]bool)
+	for _, v := range world.GetMyVehicles() {
+		if v.Selected {
+			actual[v.Id] = true
+		}
+		if !t.predicted {
+			continue
+		}
+		switch {
+		case v.Selected && !t.selected[v.Id]:
+			m.Unexpected = append(m.Unexpected, v.Id)
+		case !v.Selected && t.selected[v.Id]:
+			m.Missing = append(m.Missing, v.Id)
+		}
+	}
+	t.selected = actual
+	t.predicted = false
+	if len(m.Unexpected) == 0 && len(m.Missing) == 0 {
+		return nil
+	}
+	return m
+}
+
+// Apply predicts the effect of move, made on world, on the selection.
+func (t *SelectionTracker) Apply(world *World, move *Move) {
+	sel := Selection{
+		Group: move.Group,
+		Rect:  Rect{Left: move.Left, Top: move.Top, Right: move.Right, Bottom: move.Bottom},
+		Type:  move.Vehicle_type,
+	}
+	switch move.Action {
+	case Action_Clear_And_Select:
+		t.selected = make(map[int64]bool)
+		fallthrough
+	case Action_Add_To_Selection:
+		for _, v := range sel.Vehicles(world) {
+			t.selected[v.Id] = true
+		}
+	case Action_Deselect:
+		for _, v := range sel.Vehicles(world) {
+			delete(t.selected, v.Id)
+		}
+	default:
+		return
+	}
+	t.predicted = true
+}
+
+func (t *SelectionTracker) IsSelected(id int64) bool {
+	return t.selected[id]
+}
+
+// Selected returns the ids of the vehicles predicted to be selected, in order.
+func (t *SelectionTracker) Selected() []int64 {
+	r := make([]int64, 0, len(t.selected))
+	for id := range t.selected {
+		r = append(r, id)
+	}
+	sort.Slice(r, func(i, j int) bool { return r[i] < r[j] })
+	return r
+}
@@ -0,0 +1,107 @@
+package command
+
+import (
+	. "codewars"
+	"reflect"
+	"testing"
+)
+
+// serverSelects marks the vehicles of world with ids, and only them, as
+// selected, as the server reports them on the next tick.
+func serverSelects(world *World, ids ...int64) {
+	for _, v := range world.VehicleById {
+		v.Selected = false
+	}
+	for _, id := range ids {
+		world.VehicleById[id].Selected = true
+	}
+	world.TickIndex++
+}
+
+func TestSelectionTracker(t *testing.T) {
+	world := newWorld(0,
+		vehicle(1, 1, Vehicle_Tank, 100, 100),
+		vehicle(2, 1, Vehicle_Tank, 200, 100),
+		vehicle(3, 1, Vehicle_Ifv, 100, 300),
+		vehicle(4, 2, Vehicle_Tank, 150, 100),
+	)
+	tracker := NewSelectionTracker()
+	if m := tracker.Update(world); m != nil {
+		t.Fatalf("mismatch without a prediction: %v", m)
+	}
+
+	for _, c := range []struct {
+		name string
+		move *Move
+		want []int64
+	}{
+		{"clear and select", new(Move).ClearAndSelect(Rect{Right: 300, Bottom: 200}, Vehicle_Unknown), []int64{1, 2}},
+		{"add", new(Move).AddToSelection(Rect{Right: 1024, Bottom: 1024}, Vehicle_Ifv), []int64{1, 2, 3}},
+		{"deselect", new(Move).Deselect(Rect{Left: 50, Top: 50, Right: 150, Bottom: 150}, Vehicle_Unknown), []int64{2, 3}},
+		{"select again", new(Move).ClearAndSelect(Rect{Right: 1024, Bottom: 1024}, Vehicle_Tank), []int64{1, 2}},
+		{"no selection change", new(Move).Assign(5), []int64{1, 2}},
+	} {
+		tracker.Apply(world, c.move)
+		if got := tracker.Selected(); !reflect.DeepEqual(got, c.want) {
+			t.Fatalf("%s: predicted %v, want %v", c.name, got, c.want)
+		}
+		serverSelects(world, c.want...)
+		if m := tracker.Update(world); m != nil {
+			t.Fatalf("%s: %v", c.name, m)
+		}
+		if got := tracker.Selected(); !reflect.DeepEqual(got, c.want) {
+			t.Fatalf("%s: reconciled %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestSelectionTrackerMismatch(t *testing.T) {
+	world := newWorld(0,
+		vehicle(1, 1, Vehicle_Tank, 100, 100),
+		vehicle(2, 1, Vehicle_Tank, 200, 100),
+		vehicle(3, 1, Vehicle_Ifv, 100, 300),
+	)
+	tracker := NewSelectionTracker()
+	tracker.Update(world)
+	tracker.Apply(world, new(Move).ClearAndSelect(Rect{Right: 1024, Bottom: 1024}, Vehicle_Tank))
+
+	// The server ignored the move and kept the IFV and tank 1 selected.
+	serverSelects(world, 1, 3)
+	m := tracker.Update(world)
+	if m == nil {
+		t.Fatal("no mismatch reported")
+	}
+	if m.TickIndex != 1 || !reflect.DeepEqual(m.Unexpected, []int64{3}) || !reflect.DeepEqual(m.Missing, []int64{2}) {
+		t.Errorf("mismatch = %+v, want tick 1, 3 unexpected, 2 missing", *m)
+	}
+	// The server is right from then on.
+	if got := tracker.Selected(); !reflect.DeepEqual(got, []int64{1, 3}) || !tracker.IsSelected(3) || tracker.IsSelected(2) {
+		t.Errorf("selection after the update = %v", got)
+	}
+}
+
+func TestSelectionTrackerDeath(t *testing.T) {
+	world := newWorld(0,
+		vehicle(1, 1, Vehicle_Tank, 100, 100),
+		vehicle(2, 1, Vehicle_Tank, 200, 100),
+	)
+	tracker := NewSelectionTracker()
+	tracker.Update(world)
+	tracker.Apply(world, new(Move).ClearAndSelect(Rect{Right: 1024, Bottom: 1024}, Vehicle_Tank))
+
+	// Tank 2 is destroyed before the server reports the selection.
+	delete(world.VehicleById, 2)
+	serverSelects(world, 1)
+	if m := tracker.Update(world); m != nil {
+		t.Errorf("destroyed vehicle reported as a mismatch: %+v", *m)
+	}
+	if got := tracker.Selected(); !reflect.DeepEqual(got, []int64{1}) || tracker.IsSelected(2) {
+		t.Errorf("selection after the death = %v", got)
+	}
+
+	// Selecting the area again only predicts the survivor.
+	tracker.Apply(world, new(Move).ClearAndSelect(Rect{Right: 1024, Bottom: 1024}, Vehicle_Unknown))
+	if got := tracker.Selected(); !reflect.DeepEqual(got, []int64{1}) {
+		t.Errorf("predicted %v, want [1]", got)
+	}
+}
@@ -0,0 +1,73 @@
+package codewars
+
+import (
+	"fmt"
+	"strings"
+)
+
+// enumInfo describes how an enum is spelled in text and on the wire. The wire
+// code of a value is its index in names; the protocol encodes a missing value
+// as -1, which only types with a nullName accept.
+type enumInfo struct {
+	kind     string
+	names    []string
+	nullName string
+}
+
+const nullCode byte = 0xFF
+
+func (e *enumInfo) name(v int) (string, bool) {
+	if v == -1 && e.nullName != "" {
+		return e.nullName, true
+	}
+	if v < 0 || v >= len(e.names) {
+		return fmt.Sprintf("%s(%d)", e.kind, v), false
+	}
+	return e.names[v], true
+}
+
+func (e *enumInfo) text(v int) string {
+	s, _ := e.name(v)
+	return s
+}
+
+func (e *enumInfo) marshal(v int) ([]byte, error) {
+	s, ok := e.name(v)
+	if !ok {
+		return nil, fmt.Errorf("codewars: invalid %s %d", e.kind, v)
+	}
+	return []byte(s), nil
+}
+
+func (e *enumInfo) unmarshal(text []byte) (int, error) {
+	s := string(text)
+	if e.nullName != "" && strings.EqualFold(s, e.nullName) {
+		return -1, nil
+	}
+	for i, name := range e.names {
+		if strings.EqualFold(s, name) {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("codewars: unknown %s %q", e.kind, s)
+}
+
+func (e *enumInfo) encode(v int) (byte, error) {
+	if v == -1 && e.nullName != "" {
+		return nullCode, nil
+	}
+	if v < 0 || v >= len(e.names) {
+		return 0, fmt.Errorf("codewars: invalid %s %d", e.kind, v)
+	}
+	return byte(v), nil
+}
+
+func (e *enumInfo) decode(b byte) (int, error) {
+	if b == nullCode && e.nullName != "" {
+		return -1, nil
+	}
+	if int(b) >= len(e.names) {
+		return 0, fmt.Errorf("codewars: unknown %s code %d", e.kind, int8(b))
+	}
+	return int(b), nil
+}
@@ -3,10 +3,33 @@ package codewars
 type FacilityType int
 
 const (
-	Facility_Control_Center FacilityType = iota
-	Facility_Vehicle_Factory
+	Facility_Control_Center  FacilityType = 0
+	Facility_Vehicle_Factory FacilityType = 1
 )
 
+var facilityTypeInfo = enumInfo{"FacilityType", []string{"Control_Center", "Vehicle_Factory"}, ""}
+
+func (t FacilityType) String() string { return facilityTypeInfo.text(int(t)) }
+
+func (t FacilityType) MarshalText() ([]byte, error) { return facilityTypeInfo.marshal(int(t)) }
+
+func (t *FacilityType) UnmarshalText(text []byte) error {
+	v, err := facilityTypeInfo.unmarshal(text)
+	if err != nil {
+		return err
+	}
+	*t = FacilityType(v)
+	return nil
+}
+
+// Encode returns the wire code of t.
+func (t FacilityType) Encode() (byte, error) { return facilityTypeInfo.encode(int(t)) }
+
+func DecodeFacilityType(b byte) (FacilityType, error) {
+	v, err := facilityTypeInfo.decode(b)
+	return FacilityType(v), err
+}
+
 type Facility struct {
 	Id                 int64
 	Type               FacilityType
@@ -16,6 +39,26 @@ type Facility struct {
 	CapturePoints      float64
 	VehicleType        VehicleType
 	ProductionProgress int
+
+	// Width and Height are not sent with every facility: the runner fills
+	// them in from Game.FacilityWidth and Game.FacilityHeight.
+	Width, Height float64
+}
+
+// GetX returns the x of the center of the facility, so that Facility is a
+// Point.
+func (f *Facility) GetX() float64 { return f.Left + f.Width/2 }
+
+func (f *Facility) GetY() float64 { return f.Top + f.Height/2 }
+
+// Rect returns the area of the facility.
+func (f *Facility) Rect() Rect {
+	return Rect{Left: f.Left, Top: f.Top, Right: f.Left + f.Width, Bottom: f.Top + f.Height}
+}
+
+// Contains tells whether (x, y) is on the facility, borders included.
+func (f *Facility) Contains(x, y float64) bool {
+	return x >= f.Left && x <= f.Left+f.Width && y >= f.Top && y <= f.Top+f.Height
 }
 
 /*
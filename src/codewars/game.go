@@ -90,4 +90,109 @@ type Game struct {
 	FacilityCapturePointsPerVehiclePerTick float64
 	FacilityWidth                          float64
 	FacilityHeight                         float64
+
+	BaseTacticalNuclearStrikeCooldown                     int
+	TacticalNuclearStrikeCooldownDecreasePerControlCenter int
+	MaxTacticalNuclearStrikeDamage                        float64
+	TacticalNuclearStrikeRadius                           float64
+	TacticalNuclearStrikeDelay                            int
+}
+
+// NewGame returns the rules of the 2017 championship, for local games and
+// simulations. Strategies talking to a real server should use the Game it sends.
+func NewGame() *Game {
+	return &Game{
+		TickCount:                              20000,
+		WorldWidth:                             1024,
+		WorldHeight:                            1024,
+		FacilityCaptureScore:                   100,
+		VehicleEliminationScore:                1,
+		ActionDetectionInterval:                60,
+		BaseActionCount:                        12,
+		AdditionalActionCountPerControlCenter:  3,
+		MaxUnitGroup:                           100,
+		TerrainWeatherMapColumnCount:           32,
+		TerrainWeatherMapRowCount:              32,
+		PlainTerrainVisionFactor:               1,
+		PlainTerrainStealthFactor:              1,
+		PlainTerrainSpeedFactor:                1,
+		SwampTerrainVisionFactor:               1,
+		SwampTerrainStealthFactor:              1,
+		SwampTerrainSpeedFactor:                0.6,
+		ForestTerrainVisionFactor:              0.8,
+		ForestTerrainStealthFactor:             0.6,
+		ForestTerrainSpeedFactor:               0.8,
+		ClearWeatherVisionFactor:               1,
+		ClearWeatherStealthFactor:              1,
+		ClearWeatherSpeedFactor:                1,
+		CloudWeatherVisionFactor:               0.8,
+		CloudWeatherStealthFactor:              0.8,
+		CloudWeatherSpeedFactor:                0.8,
+		RainWeatherVisionFactor:                0.6,
+		RainWeatherStealthFactor:               0.6,
+		RainWeatherSpeedFactor:                 0.6,
+		VehicleRadius:                          2,
+		TankDurability:                         100,
+		TankSpeed:                              0.3,
+		TankVisionRange:                        80,
+		TankGroundAttackRange:                  20,
+		TankAerialAttackRange:                  18,
+		TankGroundDamage:                       100,
+		TankAerialDamage:                       60,
+		TankGroundDefence:                      80,
+		TankAerialDefence:                      60,
+		TankAttackCooldownTicks:                60,
+		TankProductionCost:                     60,
+		IfvDurability:                          100,
+		IfvSpeed:                               0.4,
+		IfvVisionRange:                         80,
+		IfvGroundAttackRange:                   18,
+		IfvAerialAttackRange:                   20,
+		IfvGroundDamage:                        90,
+		IfvAerialDamage:                        80,
+		IfvGroundDefence:                       60,
+		IfvAerialDefence:                       80,
+		IfvAttackCooldownTicks:                 60,
+		IfvProductionCost:                      60,
+		ArrvDurability:                         100,
+		ArrvSpeed:                              0.4,
+		ArrvVisionRange:                        60,
+		ArrvGroundDefence:                      50,
+		ArrvAerialDefence:                      40,
+		ArrvProductionCost:                     60,
+		ArrvRepairRange:                        10,
+		ArrvRepairSpeed:                        0.1,
+		HelicopterDurability:                   100,
+		HelicopterSpeed:                        0.9,
+		HelicopterVisionRange:                  100,
+		HelicopterGroundAttackRange:            20,
+		HelicopterAerialAttackRange:            18,
+		HelicopterGroundDamage:                 100,
+		HelicopterAerialDamage:                 80,
+		HelicopterGroundDefence:                40,
+		HelicopterAerialDefence:                40,
+		HelicopterAttackCooldownTicks:          60,
+		HelicopterProductionCost:               75,
+		FighterDurability:                      100,
+		FighterSpeed:                           1.2,
+		FighterVisionRange:                     120,
+		FighterGroundAttackRange:               20,
+		FighterAerialAttackRange:               20,
+		FighterGroundDamage:                    0,
+		FighterAerialDamage:                    90,
+		FighterGroundDefence:                   70,
+		FighterAerialDefence:                   70,
+		FighterAttackCooldownTicks:             60,
+		FighterProductionCost:                  90,
+		MaxFacilityCapturePoints:               100,
+		FacilityCapturePointsPerVehiclePerTick: 0.005,
+		FacilityWidth:                          64,
+		FacilityHeight:                         64,
+
+		BaseTacticalNuclearStrikeCooldown:                     1200,
+		TacticalNuclearStrikeCooldownDecreasePerControlCenter: 60,
+		MaxTacticalNuclearStrikeDamage:                        99,
+		TacticalNuclearStrikeRadius:                           50,
+		TacticalNuclearStrikeDelay:                            30,
+	}
 }
@@ -0,0 +1,155 @@
+package geometry
+
+import (
+	"codewars"
+	"reflect"
+	"testing"
+)
+
+func TestConvexHull(t *testing.T) {
+	for _, c := range []struct {
+		name   string
+		points []Vec2
+		want   Polygon
+	}{
+		{
+			name:   "square with inner, edge and repeated points",
+			points: []Vec2{{5, 5}, {10, 10}, {0, 0}, {5, 0}, {10, 0}, {0, 10}, {10, 5}, {0, 0}, {10, 10}},
+			want:   Polygon{{0, 0}, {10, 0}, {10, 10}, {0, 10}},
+		},
+		{
+			name:   "triangle",
+			points: []Vec2{{0, 5}, {4, 0}, {8, 5}, {4, 2}},
+			want:   Polygon{{0, 5}, {4, 0}, {8, 5}},
+		},
+		{
+			name:   "collinear",
+			points: []Vec2{{2, 2}, {0, 0}, {1, 1}, {3, 3}},
+			want:   Polygon{{0, 0}, {3, 3}},
+		},
+		{
+			name:   "one point repeated",
+			points: []Vec2{{1, 2}, {1, 2}, {1, 2}},
+			want:   Polygon{{1, 2}},
+		},
+		{
+			name:   "two points",
+			points: []Vec2{{3, 0}, {1, 0}, {3, 0}},
+			want:   Polygon{{1, 0}, {3, 0}},
+		},
+		{name: "none"},
+	} {
+		got := ConvexHull(c.points)
+		if len(got) != len(c.want) || len(got) > 0 && !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: ConvexHull = %v, want %v", c.name, got, c.want)
+		}
+		if len(got) >= 3 && got.Area() <= 0 {
+			t.Errorf("%s: hull %v not clockwise on screen", c.name, got)
+		}
+	}
+}
+
+func TestPolygonContains(t *testing.T) {
+	// A U opening upwards on screen.
+	u := Polygon{{0, 0}, {2, 0}, {2, 4}, {4, 4}, {4, 0}, {6, 0}, {6, 6}, {0, 6}}
+	for _, c := range []struct {
+		p    Vec2
+		want bool
+	}{
+		{Vec2{1, 1}, true},
+		{Vec2{5, 1}, true},
+		{Vec2{3, 5}, true},
+		{Vec2{3, 2}, false},
+		{Vec2{-1, 3}, false},
+		{Vec2{7, 3}, false},
+		// edges and vertices
+		{Vec2{0, 3}, true},
+		{Vec2{3, 4}, true},
+		{Vec2{2, 2}, true},
+		{Vec2{6, 6}, true},
+		{Vec2{4, 0}, true},
+		{Vec2{3, 0}, false},
+	} {
+		if got := u.Contains(c.p); got != c.want {
+			t.Errorf("Contains(%v) = %v, want %v", c.p, got, c.want)
+		}
+	}
+	if a := u.Area(); a != 28 {
+		t.Errorf("Area() = %v, want 28", a)
+	}
+}
+
+func TestSegmentIntersection(t *testing.T) {
+	s := Segment{Vec2{0, 0}, Vec2{4, 0}}
+	for _, c := range []struct {
+		name string
+		t    Segment
+		p    Vec2
+		ok   bool
+	}{
+		{"crossing", Segment{Vec2{2, -2}, Vec2{2, 2}}, Vec2{2, 0}, true},
+		{"parallel", Segment{Vec2{0, 1}, Vec2{4, 1}}, Vec2{}, false},
+		{"collinear apart", Segment{Vec2{5, 0}, Vec2{8, 0}}, Vec2{}, false},
+		{"collinear overlapping", Segment{Vec2{3, 0}, Vec2{8, 0}}, Vec2{3, 0}, true},
+		{"collinear containing", Segment{Vec2{-1, 0}, Vec2{8, 0}}, Vec2{0, 0}, true},
+		{"end to end", Segment{Vec2{4, 0}, Vec2{6, 3}}, Vec2{4, 0}, true},
+		{"end on the middle", Segment{Vec2{1, 0}, Vec2{1, 5}}, Vec2{1, 0}, true},
+		{"short of it", Segment{Vec2{1, 0.5}, Vec2{1, 5}}, Vec2{}, false},
+		{"beyond the end", Segment{Vec2{5, -1}, Vec2{5, 1}}, Vec2{}, false},
+	} {
+		p, ok := s.Intersection(c.t)
+		if ok != c.ok || p != c.p {
+			t.Errorf("%s: Intersection = %v, %v, want %v, %v", c.name, p, ok, c.p, c.ok)
+		}
+		if back := c.t.Intersects(s); back != c.ok {
+			t.Errorf("%s: reversed Intersects = %v, want %v", c.name, back, c.ok)
+		}
+	}
+	if !s.IntersectsRect(Rect{Left: 1, Top: -1, Right: 2, Bottom: 1}) || s.IntersectsRect(Rect{Left: 1, Top: 1, Right: 2, Bottom: 2}) {
+		t.Error("IntersectsRect wrong for a rectangle across and one beside")
+	}
+}
+
+func TestCircle(t *testing.T) {
+	c := Circle{Vec2{0, 0}, 5}
+	if !c.Contains(Vec2{3, 4}) || c.Contains(Vec2{3, 4.01}) {
+		t.Error("Contains wrong at the border")
+	}
+	if !c.IntersectsCircle(Circle{Vec2{8, 6}, 5}) || c.IntersectsCircle(Circle{Vec2{8, 6}, 4.9}) {
+		t.Error("IntersectsCircle wrong for tangent circles")
+	}
+	// The corner at (4, 4) is just out of reach, the side at x = 4.9 is not.
+	if c.IntersectsRect(Rect{Left: 4, Top: 4, Right: 10, Bottom: 10}) || !c.IntersectsRect(Rect{Left: 4.9, Top: -1, Right: 10, Bottom: 1}) {
+		t.Error("IntersectsRect wrong near a corner")
+	}
+	if !c.IntersectsSegment(Segment{Vec2{-10, 5}, Vec2{10, 5}}) || c.IntersectsSegment(Segment{Vec2{6, -10}, Vec2{6, 10}}) {
+		t.Error("IntersectsSegment wrong for a tangent and a missing segment")
+	}
+}
+
+func TestFacilityBounds(t *testing.T) {
+	f := &codewars.Facility{Left: 64, Top: 128, Width: 64, Height: 32}
+	if c := Of(f); c != (Vec2{96, 144}) {
+		t.Errorf("center = %v, want (96, 144)", c)
+	}
+	if r := Rect(f.Rect()); r != (Rect{Left: 64, Top: 128, Right: 128, Bottom: 160}) {
+		t.Errorf("Rect() = %+v", r)
+	}
+	for _, c := range []struct {
+		x, y float64
+		want bool
+	}{
+		{96, 144, true},
+		{64, 128, true},
+		{128, 160, true},
+		{128.01, 150, false},
+		{100, 127.99, false},
+	} {
+		if got := f.Contains(c.x, c.y); got != c.want {
+			t.Errorf("Contains(%v, %v) = %v, want %v", c.x, c.y, got, c.want)
+		}
+		if got := Rect(f.Rect()).Contains(Vec2{c.x, c.y}); got != c.want {
+			t.Errorf("Rect().Contains(%v, %v) = %v, want %v", c.x, c.y, got, c.want)
+		}
+	}
+}
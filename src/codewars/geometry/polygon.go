@@ -0,0 +1,85 @@
+package geometry
+
+import (
+	"sort"
+)
+
+// Polygon is a simple polygon given by its vertices in order, without
+// repeating the first one at the end.
+type Polygon []Vec2
+
+// ConvexHull returns the smallest convex polygon containing points, clockwise
+// on screen from the leftmost point. Points on its edges are left out. Fewer
+// than three distinct points make a degenerate hull of as many vertices.
+func ConvexHull(points []Vec2) Polygon {
+	ps := append([]Vec2(nil), points...)
+	sort.Slice(ps, func(i, j int) bool {
+		return ps[i].X < ps[j].X || ps[i].X == ps[j].X && ps[i].Y < ps[j].Y
+	})
+	distinct := ps[:0]
+	for i, p := range ps {
+		if i == 0 || p != ps[i-1] {
+			distinct = append(distinct, p)
+		}
+	}
+	ps = distinct
+	if len(ps) < 3 {
+		return Polygon(ps)
+	}
+
+	// Andrew's monotone chain, the lower chain first then the upper one.
+	hull := make(Polygon, 0, 2*len(ps))
+	for pass := 0; pass < 2; pass++ {
+		start := len(hull)
+		for _, p := range ps {
+			for len(hull) >= start+2 && hull[len(hull)-1].Sub(hull[len(hull)-2]).Cross(p.Sub(hull[len(hull)-2])) <= 0 {
+				hull = hull[:len(hull)-1]
+			}
+			hull = append(hull, p)
+		}
+		hull = hull[:len(hull)-1]
+		for i, j := 0, len(ps)-1; i < j; i, j = i+1, j-1 {
+			ps[i], ps[j] = ps[j], ps[i]
+		}
+	}
+	return hull
+}
+
+// Contains tells whether p is inside the polygon or on its border.
+func (poly Polygon) Contains(p Vec2) bool {
+	inside := false
+	for i := range poly {
+		a, b := poly[i], poly[(i+1)%len(poly)]
+		if onSegment(Segment{a, b}, p) && b.Sub(a).Cross(p.Sub(a)) == 0 {
+			return true
+		}
+		if (a.Y > p.Y) != (b.Y > p.Y) && p.X < a.X+(p.Y-a.Y)*(b.X-a.X)/(b.Y-a.Y) {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// Area returns the area of the polygon, positive when it is clockwise on
+// screen.
+func (poly Polygon) Area() float64 {
+	a := 0.0
+	for i := range poly {
+		a += poly[i].Cross(poly[(i+1)%len(poly)])
+	}
+	return a / 2
+}
+
+// Perimeter returns the total length of the edges.
+func (poly Polygon) Perimeter() float64 {
+	l := 0.0
+	for i := range poly {
+		l += poly[i].Dist(poly[(i+1)%len(poly)])
+	}
+	return l
+}
+
+// Bounds returns the smallest rectangle containing the polygon.
+func (poly Polygon) Bounds() Rect {
+	return Bounds(poly)
+}
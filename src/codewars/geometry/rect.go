@@ -0,0 +1,94 @@
+package geometry
+
+import (
+	"codewars"
+	"math"
+)
+
+// Rect is an axis-aligned rectangle with the fields of codewars.Rect, Top <
+// Bottom, so the two convert into each other, e.g.
+// move.ClearAndSelect(codewars.Rect(r), t).
+type Rect codewars.Rect
+
+// Bounds returns the smallest rectangle containing points, the zero Rect if
+// there are none.
+func Bounds(points []Vec2) Rect {
+	if len(points) == 0 {
+		return Rect{}
+	}
+	r := Rect{Left: points[0].X, Top: points[0].Y, Right: points[0].X, Bottom: points[0].Y}
+	for _, p := range points[1:] {
+		r.Left = math.Min(r.Left, p.X)
+		r.Top = math.Min(r.Top, p.Y)
+		r.Right = math.Max(r.Right, p.X)
+		r.Bottom = math.Max(r.Bottom, p.Y)
+	}
+	return r
+}
+
+func (r Rect) Width() float64  { return r.Right - r.Left }
+func (r Rect) Height() float64 { return r.Bottom - r.Top }
+func (r Rect) Center() Vec2    { return Vec2{(r.Left + r.Right) / 2, (r.Top + r.Bottom) / 2} }
+
+// Empty reports whether r contains no point at all.
+func (r Rect) Empty() bool {
+	return r.Left > r.Right || r.Top > r.Bottom
+}
+
+// Contains tells whether p is within r, borders included, the way the server
+// selects vehicles.
+func (r Rect) Contains(p Vec2) bool {
+	return p.X >= r.Left && p.X <= r.Right && p.Y >= r.Top && p.Y <= r.Bottom
+}
+
+func (r Rect) Intersects(s Rect) bool {
+	return !r.Intersect(s).Empty()
+}
+
+// Intersect returns the common part of r and s, which is Empty if they do not
+// overlap.
+func (r Rect) Intersect(s Rect) Rect {
+	return Rect{
+		Left:   math.Max(r.Left, s.Left),
+		Top:    math.Max(r.Top, s.Top),
+		Right:  math.Min(r.Right, s.Right),
+		Bottom: math.Min(r.Bottom, s.Bottom),
+	}
+}
+
+// Union returns the smallest rectangle containing r and s.
+func (r Rect) Union(s Rect) Rect {
+	return Rect{
+		Left:   math.Min(r.Left, s.Left),
+		Top:    math.Min(r.Top, s.Top),
+		Right:  math.Max(r.Right, s.Right),
+		Bottom: math.Max(r.Bottom, s.Bottom),
+	}
+}
+
+// Inset moves every side of r by d towards the center, or away from it if d
+// is negative.
+func (r Rect) Inset(d float64) Rect {
+	return Rect{Left: r.Left + d, Top: r.Top + d, Right: r.Right - d, Bottom: r.Bottom - d}
+}
+
+// Translate moves r by v.
+func (r Rect) Translate(v Vec2) Rect {
+	return Rect{Left: r.Left + v.X, Top: r.Top + v.Y, Right: r.Right + v.X, Bottom: r.Bottom + v.Y}
+}
+
+// Clamp returns the point of r closest to p.
+func (r Rect) Clamp(p Vec2) Vec2 {
+	return Vec2{math.Max(r.Left, math.Min(r.Right, p.X)), math.Max(r.Top, math.Min(r.Bottom, p.Y))}
+}
+
+// Corners returns the corners of r clockwise on screen from the top left one.
+func (r Rect) Corners() []Vec2 {
+	return []Vec2{{r.Left, r.Top}, {r.Right, r.Top}, {r.Right, r.Bottom}, {r.Left, r.Bottom}}
+}
+
+// Edges returns the sides of r in the order of Corners.
+func (r Rect) Edges() []Segment {
+	c := r.Corners()
+	return []Segment{{c[0], c[1]}, {c[1], c[2]}, {c[2], c[3]}, {c[3], c[0]}}
+}
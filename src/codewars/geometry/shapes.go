@@ -0,0 +1,109 @@
+package geometry
+
+import (
+	"math"
+)
+
+// Circle is a disc, e.g. the body of a vehicle or the area of a nuclear
+// strike.
+type Circle struct {
+	Center Vec2
+	Radius float64
+}
+
+// Contains tells whether p is within c, border included.
+func (c Circle) Contains(p Vec2) bool {
+	return c.Center.Dist2(p) <= c.Radius*c.Radius
+}
+
+func (c Circle) IntersectsCircle(d Circle) bool {
+	r := c.Radius + d.Radius
+	return c.Center.Dist2(d.Center) <= r*r
+}
+
+func (c Circle) IntersectsRect(r Rect) bool {
+	return c.Contains(r.Clamp(c.Center))
+}
+
+func (c Circle) IntersectsSegment(s Segment) bool {
+	return c.Contains(s.Closest(c.Center))
+}
+
+// Segment is the line segment from A to B.
+type Segment struct {
+	A, B Vec2
+}
+
+func (s Segment) Len() float64 { return s.A.Dist(s.B) }
+
+// Closest returns the point of s closest to p.
+func (s Segment) Closest(p Vec2) Vec2 {
+	d := s.B.Sub(s.A)
+	l := d.Len2()
+	if l == 0 {
+		return s.A
+	}
+	t := math.Max(0, math.Min(1, p.Sub(s.A).Dot(d)/l))
+	return s.A.Add(d.Mul(t))
+}
+
+// Dist returns the distance from p to the closest point of s.
+func (s Segment) Dist(p Vec2) float64 {
+	return s.Closest(p).Dist(p)
+}
+
+// Intersection returns the point where s and t cross. Overlapping collinear
+// segments have no single such point; for them it returns an end point of
+// one lying on the other. ok is false if the segments do not touch.
+func (s Segment) Intersection(t Segment) (p Vec2, ok bool) {
+	d, e := s.B.Sub(s.A), t.B.Sub(t.A)
+	denominator := d.Cross(e)
+	w := t.A.Sub(s.A)
+	if denominator == 0 {
+		if w.Cross(d) != 0 {
+			return Vec2{}, false
+		}
+		for _, q := range []Vec2{t.A, t.B} {
+			if onSegment(s, q) {
+				return q, true
+			}
+		}
+		for _, q := range []Vec2{s.A, s.B} {
+			if onSegment(t, q) {
+				return q, true
+			}
+		}
+		return Vec2{}, false
+	}
+	u := w.Cross(e) / denominator
+	v := w.Cross(d) / denominator
+	if u < 0 || u > 1 || v < 0 || v > 1 {
+		return Vec2{}, false
+	}
+	return s.A.Add(d.Mul(u)), true
+}
+
+func (s Segment) Intersects(t Segment) bool {
+	_, ok := s.Intersection(t)
+	return ok
+}
+
+// IntersectsRect tells whether any point of s is within r.
+func (s Segment) IntersectsRect(r Rect) bool {
+	if r.Contains(s.A) || r.Contains(s.B) {
+		return true
+	}
+	for _, e := range r.Edges() {
+		if s.Intersects(e) {
+			return true
+		}
+	}
+	return false
+}
+
+// onSegment tells whether p, known to be on the line of s, is between its
+// ends.
+func onSegment(s Segment, p Vec2) bool {
+	return p.X >= math.Min(s.A.X, s.B.X) && p.X <= math.Max(s.A.X, s.B.X) &&
+		p.Y >= math.Min(s.A.Y, s.B.Y) && p.Y <= math.Max(s.A.Y, s.B.Y)
+}
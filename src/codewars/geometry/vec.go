@@ -0,0 +1,68 @@
+// Package geometry provides the plane geometry strategies need on top of
+// codewars: vectors, rectangles, circles, segments and polygons. It uses the
+// world coordinates of the game, with y pointing down, so a positive angle
+// turns clockwise on screen, the same way Action_Rotate does.
+package geometry
+
+import (
+	"codewars"
+	"math"
+)
+
+// Vec2 is a point or a vector of the plane. It implements codewars.Point.
+type Vec2 struct {
+	X, Y float64
+}
+
+// Of returns the position of p, e.g. of a *Vehicle or a *Facility.
+func Of(p codewars.Point) Vec2 {
+	return Vec2{p.GetX(), p.GetY()}
+}
+
+func (v Vec2) GetX() float64 { return v.X }
+func (v Vec2) GetY() float64 { return v.Y }
+
+func (v Vec2) Add(u Vec2) Vec2             { return Vec2{v.X + u.X, v.Y + u.Y} }
+func (v Vec2) Sub(u Vec2) Vec2             { return Vec2{v.X - u.X, v.Y - u.Y} }
+func (v Vec2) Mul(k float64) Vec2          { return Vec2{v.X * k, v.Y * k} }
+func (v Vec2) Dot(u Vec2) float64          { return v.X*u.X + v.Y*u.Y }
+func (v Vec2) Cross(u Vec2) float64        { return v.X*u.Y - v.Y*u.X }
+func (v Vec2) Len() float64                { return math.Hypot(v.X, v.Y) }
+func (v Vec2) Len2() float64               { return v.X*v.X + v.Y*v.Y }
+func (v Vec2) Dist(u Vec2) float64         { return v.Sub(u).Len() }
+func (v Vec2) Dist2(u Vec2) float64        { return v.Sub(u).Len2() }
+func (v Vec2) Angle() float64              { return math.Atan2(v.Y, v.X) }
+func (v Vec2) Lerp(u Vec2, t float64) Vec2 { return v.Add(u.Sub(v).Mul(t)) }
+
+// Unit returns v scaled to length 1, or the zero vector if v is zero.
+func (v Vec2) Unit() Vec2 {
+	l := v.Len()
+	if l == 0 {
+		return Vec2{}
+	}
+	return Vec2{v.X / l, v.Y / l}
+}
+
+// Rotate turns v by angle radians around the origin.
+func (v Vec2) Rotate(angle float64) Vec2 {
+	sin, cos := math.Sincos(angle)
+	return Vec2{v.X*cos - v.Y*sin, v.X*sin + v.Y*cos}
+}
+
+// RotateAround turns v by angle radians around center, which is where an
+// Action_Rotate with X, Y = center and Angle = angle takes a vehicle at v.
+func (v Vec2) RotateAround(center Vec2, angle float64) Vec2 {
+	return v.Sub(center).Rotate(angle).Add(center)
+}
+
+// Centroid returns the mean of points, the zero vector if there are none.
+func Centroid(points []Vec2) Vec2 {
+	var c Vec2
+	if len(points) == 0 {
+		return c
+	}
+	for _, p := range points {
+		c = c.Add(p)
+	}
+	return c.Mul(1 / float64(len(points)))
+}
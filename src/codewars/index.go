@@ -0,0 +1,259 @@
+package codewars
+
+import (
+	"math"
+	"sort"
+)
+
+// VehicleIndex buckets the vehicles of a world into a uniform grid of square
+// cells for range queries that do not scan every vehicle. A cell size close to
+// the usual query radius, e.g. the largest attack range, works best.
+//
+// The index keeps ids and positions only and resolves vehicles through the
+// VehicleById of the last world it was updated with, so it works whether or
+// not the same *Vehicle values are reused from tick to tick.
+type VehicleIndex struct {
+	size          float64
+	columns, rows int
+	cells         [][]indexEntry
+	// cell and position within it of every indexed id
+	slots     map[int64]indexSlot
+	world     *World
+	tickIndex int
+}
+
+type indexEntry struct {
+	id   int64
+	x, y float64
+}
+
+type indexSlot struct {
+	cell, i int
+}
+
+// NewVehicleIndex returns an empty index covering the world of game. Call
+// Update with every world before querying it.
+func NewVehicleIndex(game *Game, cellSize float64) *VehicleIndex {
+	size := math.Max(cellSize, 1)
+	x := &VehicleIndex{
+		size:      size,
+		columns:   int(game.WorldWidth/size) + 1,
+		rows:      int(game.WorldHeight/size) + 1,
+		tickIndex: -1,
+	}
+	x.cells = make([][]indexEntry, x.columns*x.rows)
+	x.slots = make(map[int64]indexSlot)
+	return x
+}
+
+// Update brings the index to world. When world is the tick right after the
+// previous one, only world.NewVehicles and world.VehicleUpdate are applied;
+// otherwise the index is rebuilt from world.VehicleById.
+func (x *VehicleIndex) Update(world *World) {
+	switch {
+	case x.world != nil && world.TickIndex == x.tickIndex:
+	case x.world != nil && world.TickIndex == x.tickIndex+1:
+		for _, v := range world.NewVehicles {
+			if v != nil {
+				x.put(v.Id, v.X, v.Y)
+			}
+		}
+		for _, u := range world.VehicleUpdate {
+			switch {
+			case u == nil:
+			case u.Durability <= 0 || world.VehicleById[u.Id] == nil:
+				x.remove(u.Id)
+			default:
+				x.put(u.Id, u.X, u.Y)
+			}
+		}
+	default:
+		x.Rebuild(world)
+		return
+	}
+	x.world = world
+	x.tickIndex = world.TickIndex
+}
+
+// Rebuild indexes every vehicle of world.VehicleById from scratch.
+func (x *VehicleIndex) Rebuild(world *World) {
+	for i := range x.cells {
+		x.cells[i] = x.cells[i][:0]
+	}
+	x.slots = make(map[int64]indexSlot, len(world.VehicleById))
+	for _, v := range world.VehicleById {
+		x.put(v.Id, v.X, v.Y)
+	}
+	x.world = world
+	x.tickIndex = world.TickIndex
+}
+
+// Len returns the number of indexed vehicles.
+func (x *VehicleIndex) Len() int {
+	return len(x.slots)
+}
+
+func (x *VehicleIndex) put(id int64, px, py float64) {
+	cell := x.cell(px, py)
+	if s, ok := x.slots[id]; ok {
+		if s.cell == cell {
+			x.cells[cell][s.i].x, x.cells[cell][s.i].y = px, py
+			return
+		}
+		x.remove(id)
+	}
+	x.slots[id] = indexSlot{cell, len(x.cells[cell])}
+	x.cells[cell] = append(x.cells[cell], indexEntry{id, px, py})
+}
+
+func (x *VehicleIndex) remove(id int64) {
+	s, ok := x.slots[id]
+	if !ok {
+		return
+	}
+	entries := x.cells[s.cell]
+	last := len(entries) - 1
+	if s.i != last {
+		entries[s.i] = entries[last]
+		x.slots[entries[s.i].id] = s
+	}
+	x.cells[s.cell] = entries[:last]
+	delete(x.slots, id)
+}
+
+func (x *VehicleIndex) column(px float64) int {
+	return int(math.Max(0, math.Min(float64(x.columns-1), px/x.size)))
+}
+
+func (x *VehicleIndex) row(py float64) int {
+	return int(math.Max(0, math.Min(float64(x.rows-1), py/x.size)))
+}
+
+func (x *VehicleIndex) cell(px, py float64) int {
+	return x.column(px)*x.rows + x.row(py)
+}
+
+// visit calls f for the entries within r, borders included, in no
+// particular order, until f returns false.
+func (x *VehicleIndex) visit(r Rect, f func(e *indexEntry) bool) {
+	if x.world == nil || r.Left > r.Right || r.Top > r.Bottom {
+		return
+	}
+	for i, right := x.column(r.Left), x.column(r.Right); i <= right; i++ {
+		for j, bottom := x.row(r.Top), x.row(r.Bottom); j <= bottom; j++ {
+			cell := x.cells[i*x.rows+j]
+			for k := range cell {
+				e := &cell[k]
+				if e.x < r.Left || e.x > r.Right || e.y < r.Top || e.y > r.Bottom {
+					continue
+				}
+				if !f(e) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// EachWithin calls f for the vehicles whose center is at most radius away
+// from p, in no particular order, until f returns false. Unlike Within it
+// does not allocate.
+func (x *VehicleIndex) EachWithin(p Point, radius float64, f func(v *Vehicle) bool) {
+	px, py := p.GetX(), p.GetY()
+	r := Rect{Left: px - radius, Top: py - radius, Right: px + radius, Bottom: py + radius}
+	x.visit(r, func(e *indexEntry) bool {
+		dx, dy := e.x-px, e.y-py
+		if dx*dx+dy*dy > radius*radius {
+			return true
+		}
+		if v := x.world.VehicleById[e.id]; v != nil {
+			return f(v)
+		}
+		return true
+	})
+}
+
+// Within returns the vehicles whose center is at most radius away from p,
+// ordered by id.
+func (x *VehicleIndex) Within(p Point, radius float64) []*Vehicle {
+	var r []*Vehicle
+	x.EachWithin(p, radius, func(v *Vehicle) bool {
+		r = append(r, v)
+		return true
+	})
+	sort.Slice(r, func(i, j int) bool { return r[i].Id < r[j].Id })
+	return r
+}
+
+// InRect returns the vehicles whose center is within r, borders included,
+// ordered by id.
+func (x *VehicleIndex) InRect(r Rect) []*Vehicle {
+	var vehicles []*Vehicle
+	x.visit(r, func(e *indexEntry) bool {
+		if v := x.world.VehicleById[e.id]; v != nil {
+			vehicles = append(vehicles, v)
+		}
+		return true
+	})
+	sort.Slice(vehicles, func(i, j int) bool { return vehicles[i].Id < vehicles[j].Id })
+	return vehicles
+}
+
+// Nearest returns up to k vehicles closest to p for which keep, unless it is
+// nil, returns true, the nearest first and the lowest id first on a tie.
+func (x *VehicleIndex) Nearest(p Point, k int, keep func(v *Vehicle) bool) []*Vehicle {
+	if x.world == nil || k <= 0 {
+		return nil
+	}
+	type candidate struct {
+		v *Vehicle
+		d float64
+	}
+	var found []candidate
+	less := func(a, b candidate) bool { return a.d < b.d || a.d == b.d && a.v.Id < b.v.Id }
+
+	px, py := p.GetX(), p.GetY()
+	ci, cj := int(math.Floor(px/x.size)), int(math.Floor(py/x.size))
+	for ring := 0; ; ring++ {
+		for i := ci - ring; i <= ci+ring; i++ {
+			for j := cj - ring; j <= cj+ring; j++ {
+				onRing := i == ci-ring || i == ci+ring || j == cj-ring || j == cj+ring
+				if !onRing || i < 0 || i >= x.columns || j < 0 || j >= x.rows {
+					continue
+				}
+				for _, e := range x.cells[i*x.rows+j] {
+					v := x.world.VehicleById[e.id]
+					if v == nil || keep != nil && !keep(v) {
+						continue
+					}
+					found = append(found, candidate{v, math.Hypot(e.x-px, e.y-py)})
+				}
+			}
+		}
+		if ci-ring <= 0 && ci+ring >= x.columns-1 && cj-ring <= 0 && cj+ring >= x.rows-1 {
+			break
+		}
+		if len(found) >= k {
+			// Cells beyond this ring are at least this far from p.
+			reach := math.Min(
+				math.Min(px-float64(ci-ring)*x.size, float64(ci+ring+1)*x.size-px),
+				math.Min(py-float64(cj-ring)*x.size, float64(cj+ring+1)*x.size-py),
+			)
+			sort.Slice(found, func(a, b int) bool { return less(found[a], found[b]) })
+			found = found[:k]
+			if found[k-1].d < reach {
+				break
+			}
+		}
+	}
+
+	sort.Slice(found, func(a, b int) bool { return less(found[a], found[b]) })
+	if len(found) > k {
+		found = found[:k]
+	}
+	r := make([]*Vehicle, len(found))
+	for i, c := range found {
+		r[i] = c.v
+	}
+	return r
+}
@@ -0,0 +1,254 @@
+package codewars
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+const indexTestVehicles = 1000
+
+func randomVehicle(r *rand.Rand, id int64, game *Game) *Vehicle {
+	v := &Vehicle{PlayerId: 1 + id%2, Durability: 100, MaxDurability: 100, VehicleType: VehicleType(id % 5)}
+	v.Id = id
+	v.X, v.Y = r.Float64()*game.WorldWidth, r.Float64()*game.WorldHeight
+	return v
+}
+
+// randomWorld returns the first tick of a world of n vehicles scattered at
+// random.
+func randomWorld(r *rand.Rand, game *Game, n int) *World {
+	w := &World{Width: game.WorldWidth, Height: game.WorldHeight, VehicleById: make(map[int64]*Vehicle)}
+	for id := int64(1); id <= int64(n); id++ {
+		w.NewVehicles = append(w.NewVehicles, randomVehicle(r, id, game))
+	}
+	UpdateVehicles(w.VehicleById, w.NewVehicles, nil)
+	return w
+}
+
+// nextWorld returns the tick after w, in which some vehicles moved, some
+// were destroyed and some appeared. The vehicles are copies, as the
+// simulator makes them.
+func nextWorld(r *rand.Rand, game *Game, w *World) *World {
+	next := &World{Width: w.Width, Height: w.Height, TickIndex: w.TickIndex + 1, VehicleById: make(map[int64]*Vehicle)}
+	var maxId int64
+	for id, v := range w.VehicleById {
+		c := *v
+		next.VehicleById[id] = &c
+		if id > maxId {
+			maxId = id
+		}
+	}
+	for _, v := range next.VehicleById {
+		u := &VehicleUpdate{Unit: v.Unit, Durability: v.Durability}
+		switch p := r.Float64(); {
+		case p < 0.02:
+			u.Durability = 0
+		case p < 0.5:
+			// far enough to cross cells now and then
+			u.X = clamp(v.X+r.NormFloat64()*10, 0, game.WorldWidth)
+			u.Y = clamp(v.Y+r.NormFloat64()*10, 0, game.WorldHeight)
+		default:
+			continue
+		}
+		next.VehicleUpdate = append(next.VehicleUpdate, u)
+	}
+	for i := 0; i < 10; i++ {
+		maxId++
+		next.NewVehicles = append(next.NewVehicles, randomVehicle(r, maxId, game))
+	}
+	UpdateVehicles(next.VehicleById, next.NewVehicles, next.VehicleUpdate)
+	return next
+}
+
+func clamp(v, low, high float64) float64 {
+	if v < low {
+		return low
+	}
+	if v > high {
+		return high
+	}
+	return v
+}
+
+func ids(vehicles []*Vehicle) []int64 {
+	r := make([]int64, len(vehicles))
+	for i, v := range vehicles {
+		r[i] = v.Id
+	}
+	return r
+}
+
+func sameIds(a, b []*Vehicle) bool {
+	x, y := ids(a), ids(b)
+	if len(x) != len(y) {
+		return false
+	}
+	for i := range x {
+		if x[i] != y[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func naiveWithin(w *World, p Point, radius float64) []*Vehicle {
+	var r []*Vehicle
+	for _, v := range w.GetVehicles() {
+		if v.GetDistanceToPoint(p) <= radius {
+			r = append(r, v)
+		}
+	}
+	return r
+}
+
+func naiveNearest(w *World, p Point, k int, keep func(*Vehicle) bool) []*Vehicle {
+	var r []*Vehicle
+	for _, v := range w.GetVehicles() {
+		if keep == nil || keep(v) {
+			r = append(r, v)
+		}
+	}
+	sort.SliceStable(r, func(i, j int) bool { return r[i].GetDistanceToPoint(p) < r[j].GetDistanceToPoint(p) })
+	if len(r) > k {
+		r = r[:k]
+	}
+	return r
+}
+
+// checkIndex compares random queries on x against brute force scans of w.
+func checkIndex(t *testing.T, r *rand.Rand, x *VehicleIndex, w *World) {
+	t.Helper()
+	if x.Len() != len(w.VehicleById) {
+		t.Fatalf("tick %d: Len() = %d, want %d", w.TickIndex, x.Len(), len(w.VehicleById))
+	}
+	for q := 0; q < 20; q++ {
+		// points off the world too
+		p := &Unit{X: r.Float64()*1200 - 100, Y: r.Float64()*1200 - 100}
+		radius := r.Float64() * 150
+		if got, want := x.Within(p, radius), naiveWithin(w, p, radius); !sameIds(got, want) {
+			t.Fatalf("tick %d: Within(%v, %v) = %v, want %v", w.TickIndex, *p, radius, ids(got), ids(want))
+		}
+
+		rect := Rect{Left: p.X, Top: p.Y, Right: p.X + r.Float64()*300, Bottom: p.Y + r.Float64()*300}
+		if got, want := x.InRect(rect), w.Query().InRect(rect.Left, rect.Top, rect.Right, rect.Bottom).Vehicles(); !sameIds(got, want) {
+			t.Fatalf("tick %d: InRect(%v) = %v, want %v", w.TickIndex, rect, ids(got), ids(want))
+		}
+
+		k := 1 + r.Intn(20)
+		if got, want := x.Nearest(p, k, nil), naiveNearest(w, p, k, nil); !sameIds(got, want) {
+			t.Fatalf("tick %d: Nearest(%v, %d) = %v, want %v", w.TickIndex, *p, k, ids(got), ids(want))
+		}
+		// A rare type makes the search go through many rings.
+		keep := func(v *Vehicle) bool { return v.VehicleType == Vehicle_Tank && v.Id%50 == 4 }
+		if got, want := x.Nearest(p, k, keep), naiveNearest(w, p, k, keep); !sameIds(got, want) {
+			t.Fatalf("tick %d: Nearest(%v, %d, keep) = %v, want %v", w.TickIndex, *p, k, ids(got), ids(want))
+		}
+	}
+}
+
+func TestVehicleIndex(t *testing.T) {
+	game := NewGame()
+	r := rand.New(rand.NewSource(1))
+	incremental := NewVehicleIndex(game, 32)
+	w := randomWorld(r, game, indexTestVehicles)
+	for tick := 0; tick < 50; tick++ {
+		incremental.Update(w)
+		checkIndex(t, r, incremental, w)
+
+		rebuilt := NewVehicleIndex(game, 50)
+		rebuilt.Rebuild(w)
+		checkIndex(t, r, rebuilt, w)
+
+		w = nextWorld(r, game, w)
+	}
+}
+
+func TestVehicleIndexSkippedTick(t *testing.T) {
+	game := NewGame()
+	r := rand.New(rand.NewSource(2))
+	x := NewVehicleIndex(game, 32)
+	w := randomWorld(r, game, 200)
+	x.Update(w)
+	// The updates of the tick in between are lost, so the index must be
+	// rebuilt rather than updated.
+	w = nextWorld(r, game, nextWorld(r, game, w))
+	x.Update(w)
+	checkIndex(t, r, x, w)
+}
+
+func TestVehicleIndexEmpty(t *testing.T) {
+	game := NewGame()
+	x := NewVehicleIndex(game, 32)
+	p := &Unit{X: 100, Y: 100}
+	if x.Within(p, 100) != nil || x.InRect(Rect{Right: 1000, Bottom: 1000}) != nil || x.Nearest(p, 3, nil) != nil {
+		t.Error("queries before Update found vehicles")
+	}
+	x.Update(&World{Width: game.WorldWidth, Height: game.WorldHeight})
+	if got := x.Nearest(p, 3, nil); len(got) != 0 {
+		t.Errorf("Nearest on an empty world = %v", ids(got))
+	}
+}
+
+// The benchmarks count, for each of the vehicles, the others within the
+// range of a tank.
+
+func benchmarkWorld() (*Game, *World) {
+	game := NewGame()
+	return game, randomWorld(rand.New(rand.NewSource(1)), game, indexTestVehicles)
+}
+
+func BenchmarkIndex(b *testing.B) {
+	game, w := benchmarkWorld()
+	x := NewVehicleIndex(game, game.TankGroundAttackRange)
+	x.Update(w)
+	vehicles := w.GetVehicles()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, v := range vehicles {
+			n := 0
+			x.EachWithin(v, game.TankGroundAttackRange, func(*Vehicle) bool {
+				n++
+				return true
+			})
+		}
+	}
+}
+
+func BenchmarkNaive(b *testing.B) {
+	game, w := benchmarkWorld()
+	vehicles := w.GetVehicles()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, v := range vehicles {
+			n := 0
+			for _, u := range vehicles {
+				if v.GetDistanceTo(u.X, u.Y) <= game.TankGroundAttackRange {
+					n++
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkIndexUpdate(b *testing.B) {
+	game, w := benchmarkWorld()
+	next := nextWorld(rand.New(rand.NewSource(2)), game, w)
+	x := NewVehicleIndex(game, game.TankGroundAttackRange)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.Rebuild(w)
+		x.Update(next)
+	}
+}
+
+func BenchmarkIndexRebuild(b *testing.B) {
+	game, w := benchmarkWorld()
+	next := nextWorld(rand.New(rand.NewSource(2)), game, w)
+	x := NewVehicleIndex(game, game.TankGroundAttackRange)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.Rebuild(w)
+		x.Rebuild(next)
+	}
+}
@@ -10,10 +10,12 @@ type Move struct {
 	X                 float64
 	Y                 float64
 	Angle             float64
+	Factor            float64
 	Max_speed         float64
 	Max_angular_speed float64
 	Vehicle_type      VehicleType
 	Facility_id       int64
+	Vehicle_id        int64
 }
 
 func NewMove() *Move {
@@ -23,3 +25,117 @@ func NewMove() *Move {
 		Facility_id:  0,
 	}
 }
+
+// Rect is an axis-aligned rectangle in world coordinates, Top < Bottom.
+type Rect struct {
+	Left, Top, Right, Bottom float64
+}
+
+// The methods below overwrite the whole move with a single action and return
+// it. Fields an action does not mention are reset and ignored by the server.
+
+func (m *Move) reset(action ActionType) *Move {
+	*m = Move{Action: action, Vehicle_type: Vehicle_Unknown}
+	return m
+}
+
+// None makes the move do nothing.
+func (m *Move) None() *Move {
+	return m.reset(Action_None)
+}
+
+// ClearAndSelect selects our vehicles within r, only those of type t unless it
+// is Vehicle_Unknown, and deselects all others. Reads Left, Top, Right,
+// Bottom and Vehicle_type.
+func (m *Move) ClearAndSelect(r Rect, t VehicleType) *Move {
+	return m.reset(Action_Clear_And_Select).setRect(r, t)
+}
+
+// AddToSelection is like ClearAndSelect but keeps the current selection.
+func (m *Move) AddToSelection(r Rect, t VehicleType) *Move {
+	return m.reset(Action_Add_To_Selection).setRect(r, t)
+}
+
+// Deselect removes our vehicles within r, of type t unless it is
+// Vehicle_Unknown, from the selection.
+func (m *Move) Deselect(r Rect, t VehicleType) *Move {
+	return m.reset(Action_Deselect).setRect(r, t)
+}
+
+// SelectGroup selects the vehicles of group and deselects all others. Reads
+// Group.
+func (m *Move) SelectGroup(group int) *Move {
+	m.reset(Action_Clear_And_Select)
+	m.Group = group
+	return m
+}
+
+// Assign adds the selected vehicles to group. Reads Group.
+func (m *Move) Assign(group int) *Move {
+	m.reset(Action_Assign)
+	m.Group = group
+	return m
+}
+
+// Dismiss removes the selected vehicles from group. Reads Group.
+func (m *Move) Dismiss(group int) *Move {
+	m.reset(Action_Dismiss)
+	m.Group = group
+	return m
+}
+
+// Disband removes all vehicles from group. Reads Group.
+func (m *Move) Disband(group int) *Move {
+	m.reset(Action_Disband)
+	m.Group = group
+	return m
+}
+
+// MoveBy moves the selected vehicles by (dx, dy), no faster than maxSpeed
+// unless it is zero. Reads X, Y and Max_speed.
+func (m *Move) MoveBy(dx, dy, maxSpeed float64) *Move {
+	m.reset(Action_Move)
+	m.X, m.Y, m.Max_speed = dx, dy, maxSpeed
+	return m
+}
+
+// Rotate turns the selected vehicles around (x, y) by angle radians, no
+// faster than maxSpeed and maxAngularSpeed unless they are zero. Reads X, Y,
+// Angle, Max_speed and Max_angular_speed.
+func (m *Move) Rotate(x, y, angle, maxSpeed, maxAngularSpeed float64) *Move {
+	m.reset(Action_Rotate)
+	m.X, m.Y, m.Angle = x, y, angle
+	m.Max_speed, m.Max_angular_speed = maxSpeed, maxAngularSpeed
+	return m
+}
+
+// Scale moves the selected vehicles away from (x, y), or towards it for a
+// factor below one, multiplying their distance to it by factor. Reads X, Y,
+// Factor and Max_speed.
+func (m *Move) Scale(x, y, factor, maxSpeed float64) *Move {
+	m.reset(Action_Scale)
+	m.X, m.Y, m.Factor, m.Max_speed = x, y, factor, maxSpeed
+	return m
+}
+
+// SetupProduction makes our factory facilityId produce vehicles of type t, or
+// stop with Vehicle_Unknown. Reads Facility_id and Vehicle_type.
+func (m *Move) SetupProduction(facilityId int64, t VehicleType) *Move {
+	m.reset(Action_Setup_Vehicle_Production)
+	m.Facility_id, m.Vehicle_type = facilityId, t
+	return m
+}
+
+// NuclearStrike launches a tactical nuclear strike at (x, y), which our
+// vehicle vehicleId must see until it lands. Reads Vehicle_id, X and Y.
+func (m *Move) NuclearStrike(vehicleId int64, x, y float64) *Move {
+	m.reset(Action_Tactical_Nuclear_Strike)
+	m.Vehicle_id, m.X, m.Y = vehicleId, x, y
+	return m
+}
+
+func (m *Move) setRect(r Rect, t VehicleType) *Move {
+	m.Left, m.Top, m.Right, m.Bottom = r.Left, r.Top, r.Right, r.Bottom
+	m.Vehicle_type = t
+	return m
+}
@@ -7,4 +7,17 @@ type Player struct {
 	StrategyCrashed              bool
 	Score                        int
 	RemainingActionCooldownTicks int
+
+	RemainingNuclearStrikeCooldownTicks int
+	NextNuclearStrikeVehicleId          int64
+	NextNuclearStrikeTickIndex          int
+	NextNuclearStrikeX                  float64
+	NextNuclearStrikeY                  float64
+}
+
+// IsNuclearStrikePending reports whether the player has launched a tactical
+// nuclear strike that has not landed yet. The server sets
+// NextNuclearStrikeTickIndex to -1 when there is none.
+func (p *Player) IsNuclearStrikePending() bool {
+	return p.NextNuclearStrikeTickIndex >= 0
 }
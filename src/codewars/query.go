@@ -0,0 +1,146 @@
+package codewars
+
+import (
+	"math"
+)
+
+// VehicleQuery is a set of vehicles narrowed down by chained filters, e.g.
+//
+//	world.Query().Enemy().OfType(Vehicle_Tank).Within(v, 100).Count()
+//
+// Filters return a new query and never modify the one they are called on.
+// The vehicles stay ordered by id.
+type VehicleQuery struct {
+	me       *Player
+	vehicles []*Vehicle
+}
+
+// Query returns a query over all vehicles known in w.
+func (w *World) Query() VehicleQuery {
+	return VehicleQuery{me: w.GetMyPlayer(), vehicles: w.GetVehicles()}
+}
+
+// Vehicles returns the vehicles matched by q.
+func (q VehicleQuery) Vehicles() []*Vehicle {
+	return append([]*Vehicle(nil), q.vehicles...)
+}
+
+// Filter keeps the vehicles for which keep returns true.
+func (q VehicleQuery) Filter(keep func(*Vehicle) bool) VehicleQuery {
+	r := VehicleQuery{me: q.me}
+	for _, v := range q.vehicles {
+		if keep(v) {
+			r.vehicles = append(r.vehicles, v)
+		}
+	}
+	return r
+}
+
+// Mine keeps our vehicles. It keeps none when the world has no player of ours.
+func (q VehicleQuery) Mine() VehicleQuery {
+	return q.Filter(func(v *Vehicle) bool { return q.me != nil && v.PlayerId == q.me.Id })
+}
+
+// Enemy keeps the vehicles of the opponent, see Mine.
+func (q VehicleQuery) Enemy() VehicleQuery {
+	return q.Filter(func(v *Vehicle) bool { return q.me != nil && v.PlayerId != q.me.Id })
+}
+
+func (q VehicleQuery) OfType(t VehicleType) VehicleQuery {
+	return q.Filter(func(v *Vehicle) bool { return v.VehicleType == t })
+}
+
+func (q VehicleQuery) Aerial() VehicleQuery {
+	return q.Filter(func(v *Vehicle) bool { return v.Aerial })
+}
+
+func (q VehicleQuery) Ground() VehicleQuery {
+	return q.Filter(func(v *Vehicle) bool { return !v.Aerial })
+}
+
+// InRect keeps the vehicles whose center is within the rectangle, borders
+// included, the same way the server selects them.
+func (q VehicleQuery) InRect(left, top, right, bottom float64) VehicleQuery {
+	return q.Filter(func(v *Vehicle) bool {
+		return v.X >= left && v.X <= right && v.Y >= top && v.Y <= bottom
+	})
+}
+
+// Within keeps the vehicles whose center is at most radius away from p.
+func (q VehicleQuery) Within(p Point, radius float64) VehicleQuery {
+	x, y := p.GetX(), p.GetY()
+	return q.Filter(func(v *Vehicle) bool {
+		dx, dy := v.X-x, v.Y-y
+		return dx*dx+dy*dy <= radius*radius
+	})
+}
+
+func (q VehicleQuery) InGroup(group int) VehicleQuery {
+	return q.Filter(func(v *Vehicle) bool {
+		for _, g := range v.Groups {
+			if g == group {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// Damaged keeps the vehicles below their maximum durability.
+func (q VehicleQuery) Damaged() VehicleQuery {
+	return q.Filter(func(v *Vehicle) bool { return v.Durability < v.MaxDurability })
+}
+
+func (q VehicleQuery) Selected() VehicleQuery {
+	return q.Filter(func(v *Vehicle) bool { return v.Selected })
+}
+
+func (q VehicleQuery) Count() int {
+	return len(q.vehicles)
+}
+
+// Centroid returns the mean position of the vehicles, (0, 0) if there are
+// none.
+func (q VehicleQuery) Centroid() (x, y float64) {
+	if len(q.vehicles) == 0 {
+		return 0, 0
+	}
+	for _, v := range q.vehicles {
+		x += v.X
+		y += v.Y
+	}
+	n := float64(len(q.vehicles))
+	return x / n, y / n
+}
+
+// BoundingRect returns the smallest rectangle containing the centers of the
+// vehicles, the zero Rect if there are none. It can be passed to
+// Move.ClearAndSelect as is.
+func (q VehicleQuery) BoundingRect() Rect {
+	if len(q.vehicles) == 0 {
+		return Rect{}
+	}
+	r := Rect{Left: math.Inf(1), Top: math.Inf(1), Right: math.Inf(-1), Bottom: math.Inf(-1)}
+	for _, v := range q.vehicles {
+		r.Left = math.Min(r.Left, v.X)
+		r.Top = math.Min(r.Top, v.Y)
+		r.Right = math.Max(r.Right, v.X)
+		r.Bottom = math.Max(r.Bottom, v.Y)
+	}
+	return r
+}
+
+// Nearest returns the vehicle closest to p, the lowest id on a tie, or nil if
+// there are none.
+func (q VehicleQuery) Nearest(p Point) *Vehicle {
+	var nearest *Vehicle
+	best := math.Inf(1)
+	x, y := p.GetX(), p.GetY()
+	for _, v := range q.vehicles {
+		dx, dy := v.X-x, v.Y-y
+		if d := dx*dx + dy*dy; d < best {
+			nearest, best = v, d
+		}
+	}
+	return nearest
+}
@@ -0,0 +1,107 @@
+package codewars
+
+import (
+	"reflect"
+	"testing"
+)
+
+func queryWorld() *World {
+	w := &World{
+		Players:     []*Player{{Id: 1, Me: true}, {Id: 2}},
+		VehicleById: make(map[int64]*Vehicle),
+	}
+	for _, v := range []struct {
+		id, player int64
+		t          VehicleType
+		x, y       float64
+		selected   bool
+		groups     []int
+	}{
+		{1, 1, Vehicle_Tank, 100, 100, true, []int{1}},
+		{2, 1, Vehicle_Tank, 300, 100, false, []int{1, 2}},
+		{3, 1, Vehicle_Fighter, 120, 120, true, nil},
+		{4, 2, Vehicle_Tank, 110, 90, false, []int{1}},
+		{5, 2, Vehicle_Helicopter, 500, 500, false, nil},
+		{6, 1, Vehicle_Tank, 200, 200, true, []int{2}},
+	} {
+		w.VehicleById[v.id] = &Vehicle{
+			CircularUnit:  CircularUnit{Unit: Unit{Id: v.id, X: v.x, Y: v.y}},
+			PlayerId:      v.player,
+			Durability:    100,
+			MaxDurability: 100,
+			VehicleType:   v.t,
+			Aerial:        v.t == Vehicle_Fighter || v.t == Vehicle_Helicopter,
+			Selected:      v.selected,
+			Groups:        v.groups,
+		}
+	}
+	return w
+}
+
+func TestVehicleQueryFilters(t *testing.T) {
+	w := queryWorld()
+	q := w.Query()
+	for _, c := range []struct {
+		name string
+		q    VehicleQuery
+		want []int64
+	}{
+		{"all", q, []int64{1, 2, 3, 4, 5, 6}},
+		{"mine", q.Mine(), []int64{1, 2, 3, 6}},
+		{"enemy", q.Enemy(), []int64{4, 5}},
+		{"my tanks", q.Mine().OfType(Vehicle_Tank), []int64{1, 2, 6}},
+		{"enemy tanks", q.OfType(Vehicle_Tank).Enemy(), []int64{4}},
+		{"aerial", q.Aerial(), []int64{3, 5}},
+		{"my selected tanks", q.Mine().Selected().OfType(Vehicle_Tank), []int64{1, 6}},
+		{"in rect, borders included", q.InRect(100, 90, 200, 200), []int64{1, 3, 4, 6}},
+		{"mine in rect in group 1", q.Mine().InRect(0, 0, 1024, 150).InGroup(1), []int64{1, 2}},
+		{"group 2", q.InGroup(2), []int64{2, 6}},
+		{"within", q.Within(&Unit{X: 100, Y: 100}, 30), []int64{1, 3, 4}},
+		{"none", q.Mine().OfType(Vehicle_Helicopter), []int64{}},
+	} {
+		if got := ids(c.q.Vehicles()); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: %v, want %v", c.name, got, c.want)
+		}
+		if c.q.Count() != len(c.want) {
+			t.Errorf("%s: Count() = %d, want %d", c.name, c.q.Count(), len(c.want))
+		}
+	}
+
+	// Filters leave the query they are called on alone.
+	mine := q.Mine()
+	mine.OfType(Vehicle_Fighter)
+	if mine.Count() != 4 || q.Count() != 6 {
+		t.Errorf("filtering changed the query: %d mine, %d in all", mine.Count(), q.Count())
+	}
+}
+
+func TestVehicleQueryAggregates(t *testing.T) {
+	w := queryWorld()
+	tanks := w.Query().Mine().OfType(Vehicle_Tank)
+	if x, y := tanks.Centroid(); x != 200 || y != 400.0/3 {
+		t.Errorf("Centroid() = %v, %v, want 200, 133.33", x, y)
+	}
+	if got, want := tanks.BoundingRect(), (Rect{Left: 100, Top: 100, Right: 300, Bottom: 200}); got != want {
+		t.Errorf("BoundingRect() = %+v, want %+v", got, want)
+	}
+	if v := w.Query().Enemy().Nearest(&Unit{X: 0, Y: 0}); v == nil || v.Id != 4 {
+		t.Errorf("Nearest() = %v, want vehicle 4", v)
+	}
+
+	empty := w.Query().Mine().Aerial().OfType(Vehicle_Tank)
+	if x, y := empty.Centroid(); x != 0 || y != 0 {
+		t.Errorf("Centroid() of nothing = %v, %v, want 0, 0", x, y)
+	}
+	if r := empty.BoundingRect(); r != (Rect{}) {
+		t.Errorf("BoundingRect() of nothing = %+v", r)
+	}
+	if v := empty.Nearest(&Unit{}); v != nil || empty.Count() != 0 {
+		t.Errorf("Nearest() of nothing = %v, Count() = %d", v, empty.Count())
+	}
+
+	// Without a player of ours there is neither mine nor enemy.
+	w.Players = nil
+	if n, m := w.Query().Mine().Count(), w.Query().Enemy().Count(); n != 0 || m != 0 {
+		t.Errorf("without players: %d mine, %d enemy", n, m)
+	}
+}
@@ -0,0 +1,365 @@
+// Package route plans the way of a group of vehicles across the map.
+package route
+
+import (
+	. "codewars"
+	"codewars/geometry"
+	"container/heap"
+	"errors"
+	"math"
+)
+
+var ErrNoRoute = errors.New("codewars: no route to the destination")
+
+// Route is the way found from a start to a destination.
+type Route struct {
+	From geometry.Vec2
+	// points to go through in order, the destination last
+	Waypoints []geometry.Vec2
+	// ticks to reach each waypoint from the previous one, or from From
+	LegTicks []float64
+	Ticks    float64
+	// ticks spent on the way weighted by the stealth factor under the group,
+	// i.e. how long it is as visible as on a clear plain
+	Exposure float64
+}
+
+// ArrivalTick returns the tick at which a group leaving on tick reaches the
+// destination.
+func (r *Route) ArrivalTick(tick int) int {
+	return tick + int(math.Ceil(r.Ticks-1e-9))
+}
+
+// Moves returns the Action_Move of each leg, relative to where the previous
+// leg ends, capped at maxSpeed unless it is zero. A move replaces the one
+// before it, so make each only once the group reached the end of the
+// previous leg, about LegTicks later.
+func (r *Route) Moves(maxSpeed float64) []*Move {
+	moves := make([]*Move, len(r.Waypoints))
+	at := r.From
+	for i, w := range r.Waypoints {
+		d := w.Sub(at)
+		moves[i] = new(Move).MoveBy(d.X, d.Y, maxSpeed)
+		at = w
+	}
+	return moves
+}
+
+// Planner finds routes over a grid of square nodes laid on the world, with
+// the speed, vision and stealth factors of a node given by the terrain, for
+// ground vehicles, or the weather, for aerial ones, under it.
+//
+// With zero Exposure and Blindness the routes are the fastest. Otherwise a
+// tick spent in a node costs 1 + Exposure*stealth + Blindness*(1-vision)
+// ticks, so that e.g. helicopters take a longer way through clouds to stay
+// hidden, or around them to keep an eye out.
+type Planner struct {
+	Game  *Game
+	World *World
+	// side of the nodes, half a terrain cell if zero
+	Step      float64
+	Exposure  float64
+	Blindness float64
+}
+
+func NewPlanner(game *Game, world *World) *Planner {
+	return &Planner{Game: game, World: world}
+}
+
+// Find returns the cheapest route for a group of vehicles of type t, the
+// centers of which stay within radius of the center of the group, from from
+// to to. The whole group keeps clear of the obstacles, e.g. other
+// formations, and of the borders of the world, and moves at the pace of its
+// slowest vehicle, so the speed of a node is the lowest under the group. It
+// returns ErrNoRoute when the destination cannot be reached.
+func (p *Planner) Find(t VehicleType, from, to geometry.Vec2, radius float64, obstacles []geometry.Circle) (*Route, error) {
+	g := p.grid(t, radius, obstacles)
+	return g.find(from, to)
+}
+
+// ArrivalTick returns the tick at which a group leaving on the tick of the
+// world arrives at to by the route Find returns.
+func (p *Planner) ArrivalTick(t VehicleType, from, to geometry.Vec2, radius float64, obstacles []geometry.Circle) (int, error) {
+	r, err := p.Find(t, from, to, radius, obstacles)
+	if err != nil {
+		return 0, err
+	}
+	return r.ArrivalTick(p.World.TickIndex), nil
+}
+
+// grid holds what the search needs to know about every node.
+type grid struct {
+	step          float64
+	columns, rows int
+	speed         float64
+	// speed and stealth factors of every node, the worst under the group
+	factor  []float64
+	stealth []float64
+	// extra cost of every tick spent in every node
+	penalty []float64
+	// nodes a group centered there would stick out of the world from or
+	// overlap an obstacle
+	blocked   []bool
+	radius    float64
+	obstacles []geometry.Circle
+	width     float64
+	height    float64
+}
+
+func (p *Planner) grid(t VehicleType, radius float64, obstacles []geometry.Circle) *grid {
+	w := p.World
+	step := p.Step
+	if step <= 0 {
+		columns := p.Game.TerrainWeatherMapColumnCount
+		if columns <= 0 {
+			columns = 32
+		}
+		step = w.Width / float64(columns) / 2
+	}
+	stats := p.Game.Stats(t)
+	g := &grid{
+		step:      step,
+		columns:   int(math.Ceil(w.Width / step)),
+		rows:      int(math.Ceil(w.Height / step)),
+		speed:     stats.Speed,
+		radius:    radius,
+		obstacles: obstacles,
+		width:     w.Width,
+		height:    w.Height,
+	}
+	n := g.columns * g.rows
+	g.factor = make([]float64, n)
+	g.stealth = make([]float64, n)
+	g.penalty = make([]float64, n)
+	g.blocked = make([]bool, n)
+	speed := func(x, y float64) float64 { return w.SpeedFactorAt(p.Game, x, y, stats.Aerial) }
+	vision := func(x, y float64) float64 { return w.VisionFactorAt(p.Game, x, y, stats.Aerial) }
+	stealth := func(x, y float64) float64 { return w.StealthFactorAt(p.Game, x, y, stats.Aerial) }
+	for i := 0; i < g.columns; i++ {
+		for j := 0; j < g.rows; j++ {
+			c := g.center(i, j)
+			k := i*g.rows + j
+			g.blocked[k] = !g.clear(c)
+			g.factor[k] = sample(c, radius, speed, math.Min)
+			g.stealth[k] = sample(c, radius, stealth, math.Max)
+			g.penalty[k] = p.Exposure*g.stealth[k] + p.Blindness*(1-sample(c, radius, vision, math.Min))
+		}
+	}
+	return g
+}
+
+// sample returns the worst of f, as picked by worst, within radius of c,
+// sampled at the center and around the edge of the disc.
+func sample(c geometry.Vec2, radius float64, f func(x, y float64) float64, worst func(a, b float64) float64) float64 {
+	v := f(c.X, c.Y)
+	if radius <= 0 {
+		return v
+	}
+	for k := 0; k < 8; k++ {
+		q := c.Add(geometry.Vec2{X: radius}.Rotate(float64(k) * math.Pi / 4))
+		v = worst(v, f(q.X, q.Y))
+	}
+	return v
+}
+
+func (g *grid) center(i, j int) geometry.Vec2 {
+	return geometry.Vec2{X: (float64(i) + 0.5) * g.step, Y: (float64(j) + 0.5) * g.step}
+}
+
+func (g *grid) node(p geometry.Vec2) (int, int) {
+	i := int(math.Max(0, math.Min(float64(g.columns-1), p.X/g.step)))
+	j := int(math.Max(0, math.Min(float64(g.rows-1), p.Y/g.step)))
+	return i, j
+}
+
+// clear tells whether a group centered at c is inside the world and off the
+// obstacles.
+func (g *grid) clear(c geometry.Vec2) bool {
+	if !g.inside(c) {
+		return false
+	}
+	for _, o := range g.obstacles {
+		if g.overlaps(o, c) {
+			return false
+		}
+	}
+	return true
+}
+
+func (g *grid) inside(c geometry.Vec2) bool {
+	return c.X >= g.radius && c.Y >= g.radius && c.X <= g.width-g.radius && c.Y <= g.height-g.radius
+}
+
+func (g *grid) overlaps(o geometry.Circle, c geometry.Vec2) bool {
+	return c.Dist(o.Center) < o.Radius+g.radius
+}
+
+// leg is the way straight from a point to another.
+type leg struct {
+	ticks, exposure, cost float64
+}
+
+// leg returns the way from a to b, or false if the group would hit an
+// obstacle or leave the world on it. A group already overlapping an
+// obstacle, or sticking out of the world, at a is let out.
+func (g *grid) leg(a, b geometry.Vec2) (leg, bool) {
+	var l leg
+	s := geometry.Segment{A: a, B: b}
+	for _, o := range g.obstacles {
+		if !g.overlaps(o, a) && g.overlaps(o, s.Closest(o.Center)) {
+			return l, false
+		}
+	}
+	if g.inside(a) && !g.inside(b) {
+		return l, false
+	}
+	n := int(math.Ceil(s.Len()/(g.step/2))) + 1
+	for k := 0; k < n; k++ {
+		q := a.Lerp(b, (float64(k)+0.5)/float64(n))
+		i, j := g.node(q)
+		f := g.factor[i*g.rows+j]
+		if f <= 0 {
+			return l, false
+		}
+		ticks := s.Len() / float64(n) / (g.speed * f)
+		l.ticks += ticks
+		l.exposure += ticks * g.stealth[i*g.rows+j]
+		l.cost += ticks * (1 + g.penalty[i*g.rows+j])
+	}
+	return l, true
+}
+
+// find runs A* from the node of from to the node of to, then straightens the
+// way where going straight costs no more.
+func (g *grid) find(from, to geometry.Vec2) (*Route, error) {
+	if g.speed <= 0 {
+		return nil, ErrNoRoute
+	}
+	si, sj := g.node(from)
+	ti, tj := g.node(to)
+	start, goal := si*g.rows+sj, ti*g.rows+tj
+	if g.blocked[goal] || !g.clear(to) {
+		return nil, ErrNoRoute
+	}
+	// Going at the speed of the fastest node all the way with the penalty of
+	// the cheapest one never overestimates.
+	fastest, cheapest := 0.0, math.Inf(1)
+	for n, f := range g.factor {
+		fastest = math.Max(fastest, g.speed*f)
+		cheapest = math.Min(cheapest, g.penalty[n])
+	}
+	h := func(n int) float64 { return g.position(n).Dist(to) / fastest * (1 + cheapest) }
+
+	costs := make([]float64, len(g.factor))
+	for n := range costs {
+		costs[n] = math.Inf(1)
+	}
+	costs[start] = 0
+	previous := make([]int, len(g.factor))
+	done := make([]bool, len(g.factor))
+	open := &queue{{start, h(start)}}
+	for open.Len() > 0 {
+		n := heap.Pop(open).(item).node
+		if n == goal {
+			break
+		}
+		if done[n] {
+			continue
+		}
+		done[n] = true
+		ni, nj := n/g.rows, n%g.rows
+		for di := -1; di <= 1; di++ {
+			for dj := -1; dj <= 1; dj++ {
+				i, j := ni+di, nj+dj
+				if di == 0 && dj == 0 || i < 0 || i >= g.columns || j < 0 || j >= g.rows {
+					continue
+				}
+				m := i*g.rows + j
+				// Blocked nodes are only used to get out of where the
+				// group starts.
+				if done[m] || g.blocked[m] && !g.blocked[n] {
+					continue
+				}
+				l, ok := g.leg(g.position(n), g.position(m))
+				if !ok || costs[m] <= costs[n]+l.cost {
+					continue
+				}
+				costs[m] = costs[n] + l.cost
+				previous[m] = n
+				heap.Push(open, item{m, costs[m] + h(m)})
+			}
+		}
+	}
+	if math.IsInf(costs[goal], 1) {
+		return nil, ErrNoRoute
+	}
+
+	var nodes []geometry.Vec2
+	for n := goal; n != start; n = previous[n] {
+		nodes = append(nodes, g.position(n))
+	}
+	points := []geometry.Vec2{from}
+	for k := len(nodes) - 1; k >= 0; k-- {
+		points = append(points, nodes[k])
+	}
+	if len(points) > 1 {
+		points[len(points)-1] = to
+	} else {
+		points = append(points, to)
+	}
+	return g.straighten(points), nil
+}
+
+func (g *grid) position(n int) geometry.Vec2 {
+	return g.center(n/g.rows, n%g.rows)
+}
+
+// straighten drops the points of the way that going straight from an
+// earlier one to a later one costs no more than going through.
+func (g *grid) straighten(points []geometry.Vec2) *Route {
+	r := &Route{From: points[0]}
+	for a := 0; a < len(points)-1; {
+		b := a + 1
+		var best leg
+		via := 0.0
+		for c := a + 1; c < len(points); c++ {
+			if l, ok := g.leg(points[c-1], points[c]); ok {
+				via += l.cost
+			} else {
+				via = math.Inf(1)
+			}
+			straight, ok := g.leg(points[a], points[c])
+			if c == a+1 && !ok {
+				ticks := points[a].Dist(points[c]) / g.speed
+				straight, ok = leg{ticks, ticks, ticks}, true
+			}
+			if c == a+1 || ok && straight.cost <= via+1e-9 {
+				b, best = c, straight
+			}
+		}
+		r.Waypoints = append(r.Waypoints, points[b])
+		r.LegTicks = append(r.LegTicks, best.ticks)
+		r.Ticks += best.ticks
+		r.Exposure += best.exposure
+		a = b
+	}
+	return r
+}
+
+type item struct {
+	node     int
+	priority float64
+}
+
+type queue []item
+
+func (q queue) Len() int            { return len(q) }
+func (q queue) Less(i, j int) bool  { return q[i].priority < q[j].priority }
+func (q queue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *queue) Push(x interface{}) { *q = append(*q, x.(item)) }
+func (q *queue) Pop() interface{} {
+	old := *q
+	x := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return x
+}
@@ -0,0 +1,257 @@
+package route
+
+import (
+	. "codewars"
+	"codewars/geometry"
+	"math"
+	"testing"
+)
+
+// newWorld returns a world of game with plain terrain and clear weather.
+func newWorld(g *Game) *World {
+	w := &World{Width: g.WorldWidth, Height: g.WorldHeight}
+	w.TerrainByCellXY = make([][]TerrainType, g.TerrainWeatherMapColumnCount)
+	w.WeatherByCellXY = make([][]WeatherType, g.TerrainWeatherMapColumnCount)
+	for i := range w.TerrainByCellXY {
+		w.TerrainByCellXY[i] = make([]TerrainType, g.TerrainWeatherMapRowCount)
+		w.WeatherByCellXY[i] = make([]WeatherType, g.TerrainWeatherMapRowCount)
+	}
+	return w
+}
+
+// fill sets the cells of columns [i0, i1) and rows [j0, j1).
+func fill(w *World, i0, i1, j0, j1 int, terrain TerrainType, weather WeatherType) {
+	for i := i0; i < i1; i++ {
+		for j := j0; j < j1; j++ {
+			w.TerrainByCellXY[i][j] = terrain
+			w.WeatherByCellXY[i][j] = weather
+		}
+	}
+}
+
+// length returns how far the route goes, and how far of it where in is
+// true, sampled every unit.
+func length(r *Route, in func(p geometry.Vec2) bool) (total, inside float64) {
+	at := r.From
+	for _, w := range r.Waypoints {
+		d := at.Dist(w)
+		for s := 0.5; s < d; s++ {
+			if in(at.Lerp(w, s/d)) {
+				inside++
+			}
+		}
+		total += d
+		at = w
+	}
+	return total, inside
+}
+
+func TestFind(t *testing.T) {
+	g := NewGame()
+	speed := g.TankSpeed
+	swamp := newWorld(g)
+	// A swamp 256 wide and 96 high across the way.
+	fill(swamp, 12, 20, 3, 6, Terrain_Swamp, Weather_Clear)
+	wall := make([]geometry.Circle, 0, 30)
+	for y := 0.0; y <= g.WorldHeight; y += 40 {
+		wall = append(wall, geometry.Circle{Center: geometry.Vec2{X: 512, Y: y}, Radius: 30})
+	}
+	obstacle := geometry.Circle{Center: geometry.Vec2{X: 500, Y: 500}, Radius: 100}
+
+	for _, c := range []struct {
+		name      string
+		world     *World
+		from, to  geometry.Vec2
+		radius    float64
+		obstacles []geometry.Circle
+		err       error
+		// waypoints expected, any number more than one if zero
+		waypoints int
+		// bounds of the ticks of the route
+		minTicks, maxTicks float64
+	}{
+		{
+			name: "straight on plain", world: newWorld(g),
+			from: geometry.Vec2{X: 100, Y: 100}, to: geometry.Vec2{X: 900, Y: 100}, radius: 10,
+			waypoints: 1, minTicks: 800 / speed, maxTicks: 800/speed + 1e-6,
+		},
+		{
+			name: "around the swamp", world: swamp,
+			from: geometry.Vec2{X: 200, Y: 144}, to: geometry.Vec2{X: 800, Y: 144}, radius: 5,
+			minTicks: 600 / speed, maxTicks: 344/speed + 256/(speed*g.SwampTerrainSpeedFactor),
+		},
+		{
+			name: "around an obstacle", world: newWorld(g),
+			from: geometry.Vec2{X: 200, Y: 500}, to: geometry.Vec2{X: 800, Y: 500}, radius: 10,
+			obstacles: []geometry.Circle{obstacle},
+			minTicks:  600 / speed, maxTicks: 1000 / speed,
+		},
+		{
+			name: "goal in an obstacle", world: newWorld(g),
+			from: geometry.Vec2{X: 200, Y: 500}, to: geometry.Vec2{X: 480, Y: 500}, radius: 10,
+			obstacles: []geometry.Circle{obstacle}, err: ErrNoRoute,
+		},
+		{
+			name: "walled off", world: newWorld(g),
+			from: geometry.Vec2{X: 200, Y: 500}, to: geometry.Vec2{X: 800, Y: 500}, radius: 10,
+			obstacles: wall, err: ErrNoRoute,
+		},
+		{
+			name: "goal off the world", world: newWorld(g),
+			from: geometry.Vec2{X: 200, Y: 500}, to: geometry.Vec2{X: 1020, Y: 500}, radius: 10,
+			err: ErrNoRoute,
+		},
+		{
+			name: "start is the goal", world: newWorld(g),
+			from: geometry.Vec2{X: 300, Y: 300}, to: geometry.Vec2{X: 300, Y: 300}, radius: 10,
+			waypoints: 1,
+		},
+	} {
+		r, err := NewPlanner(g, c.world).Find(Vehicle_Tank, c.from, c.to, c.radius, c.obstacles)
+		if err != c.err {
+			t.Errorf("%s: error = %v, want %v", c.name, err, c.err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if r.From != c.from || len(r.Waypoints) == 0 || r.Waypoints[len(r.Waypoints)-1] != c.to {
+			t.Errorf("%s: route from %v through %v, want from %v to %v", c.name, r.From, r.Waypoints, c.from, c.to)
+		}
+		if c.waypoints > 0 && len(r.Waypoints) != c.waypoints || c.waypoints == 0 && len(r.Waypoints) < 2 {
+			t.Errorf("%s: waypoints %v", c.name, r.Waypoints)
+		}
+		if len(r.LegTicks) != len(r.Waypoints) {
+			t.Errorf("%s: %d legs for %d waypoints", c.name, len(r.LegTicks), len(r.Waypoints))
+		}
+		if r.Ticks < c.minTicks-1e-6 || r.Ticks > c.maxTicks {
+			t.Errorf("%s: ticks = %v, want between %v and %v", c.name, r.Ticks, c.minTicks, c.maxTicks)
+		}
+		at := r.From
+		for _, w := range r.Waypoints {
+			for _, o := range c.obstacles {
+				d := geometry.Segment{A: at, B: w}.Closest(o.Center).Dist(o.Center)
+				if d < o.Radius+c.radius-1e-6 {
+					t.Errorf("%s: leg %v-%v comes %v from an obstacle", c.name, at, w, d)
+				}
+			}
+			at = w
+		}
+	}
+}
+
+func TestExposureAndBlindness(t *testing.T) {
+	g := NewGame()
+	// Clouds do not slow down here, only hide and blind.
+	g.CloudWeatherSpeedFactor = 1
+	from, to := geometry.Vec2{X: 100, Y: 500}, geometry.Vec2{X: 900, Y: 500}
+	find := func(w *World, exposure, blindness float64) *Route {
+		p := NewPlanner(g, w)
+		p.Exposure, p.Blindness = exposure, blindness
+		r, err := p.Find(Vehicle_Helicopter, from, to, 5, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+
+	// Clouds from y = 544 down: hiding in them is worth a detour.
+	below := newWorld(g)
+	fill(below, 0, 32, 17, 32, Terrain_Plain, Weather_Cloud)
+	inCloud := func(w *World) func(p geometry.Vec2) bool {
+		return func(p geometry.Vec2) bool { return w.WeatherAt(g, p.X, p.Y) == Weather_Cloud }
+	}
+	fastest := find(below, 0, 0)
+	hidden := find(below, 10, 0)
+	if _, in := length(fastest, inCloud(below)); in > 0 || len(fastest.Waypoints) != 1 {
+		t.Errorf("fastest route %v goes through the clouds", fastest.Waypoints)
+	}
+	if total, in := length(hidden, inCloud(below)); in < total/2 {
+		t.Errorf("with Exposure only %v of %v in the clouds", in, total)
+	}
+	if hidden.Exposure >= fastest.Exposure || hidden.Ticks <= fastest.Ticks {
+		t.Errorf("with Exposure: %v ticks, exposure %v; fastest: %v ticks, exposure %v",
+			hidden.Ticks, hidden.Exposure, fastest.Ticks, fastest.Exposure)
+	}
+
+	// Clouds on the way: seeing is worth going around them.
+	across := newWorld(g)
+	fill(across, 12, 20, 14, 18, Terrain_Plain, Weather_Cloud)
+	fastest = find(across, 0, 0)
+	seeing := find(across, 0, 10)
+	_, blind := length(fastest, inCloud(across))
+	if _, in := length(seeing, inCloud(across)); blind == 0 || in > blind/10 {
+		t.Errorf("with Blindness %v in the clouds, fastest %v", in, blind)
+	}
+	if seeing.Ticks <= fastest.Ticks || seeing.Exposure <= fastest.Exposure {
+		t.Errorf("with Blindness: %v ticks, exposure %v; fastest: %v ticks, exposure %v",
+			seeing.Ticks, seeing.Exposure, fastest.Ticks, fastest.Exposure)
+	}
+	if math.Abs(fastest.Ticks-800/g.HelicopterSpeed) > 1e-6 {
+		t.Errorf("fastest ticks = %v, want %v", fastest.Ticks, 800/g.HelicopterSpeed)
+	}
+}
+
+func TestArrivalTick(t *testing.T) {
+	for _, c := range []struct {
+		legs []float64
+		want int
+	}{
+		{[]float64{10.2, 5.3}, 116},
+		{[]float64{10, 5}, 115},
+		{[]float64{0.1}, 101},
+		{nil, 100},
+	} {
+		r := &Route{LegTicks: c.legs}
+		for _, l := range c.legs {
+			r.Ticks += l
+		}
+		if got := r.ArrivalTick(100); got != c.want {
+			t.Errorf("ArrivalTick(100) with legs %v = %d, want %d", c.legs, got, c.want)
+		}
+	}
+
+	g := NewGame()
+	w := newWorld(g)
+	w.TickIndex = 250
+	fill(w, 12, 20, 3, 6, Terrain_Swamp, Weather_Clear)
+	p := NewPlanner(g, w)
+	from, to := geometry.Vec2{X: 200, Y: 144}, geometry.Vec2{X: 800, Y: 144}
+	r, err := p.Find(Vehicle_Ifv, from, to, 5, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := 0.0
+	for _, l := range r.LegTicks {
+		sum += l
+	}
+	want := w.TickIndex + int(math.Ceil(sum))
+	if got := r.ArrivalTick(w.TickIndex); got != want {
+		t.Errorf("ArrivalTick = %d, want %d", got, want)
+	}
+	if got, err := p.ArrivalTick(Vehicle_Ifv, from, to, 5, nil); got != want || err != nil {
+		t.Errorf("Planner.ArrivalTick = %d, %v, want %d", got, err, want)
+	}
+	if _, err := p.ArrivalTick(Vehicle_Ifv, from, geometry.Vec2{X: -100, Y: 144}, 5, nil); err != ErrNoRoute {
+		t.Errorf("Planner.ArrivalTick off the world: error = %v, want ErrNoRoute", err)
+	}
+}
+
+func TestMoves(t *testing.T) {
+	r := &Route{
+		From:      geometry.Vec2{X: 100, Y: 100},
+		Waypoints: []geometry.Vec2{{X: 200, Y: 100}, {X: 200, Y: 300}, {X: 50, Y: 250}},
+	}
+	want := []geometry.Vec2{{X: 100, Y: 0}, {X: 0, Y: 200}, {X: -150, Y: -50}}
+	for _, speed := range []float64{0, 0.4} {
+		moves := r.Moves(speed)
+		if len(moves) != len(want) {
+			t.Fatalf("%d moves, want %d", len(moves), len(want))
+		}
+		for i, m := range moves {
+			if m.Action != Action_Move || m.X != want[i].X || m.Y != want[i].Y || m.Max_speed != speed {
+				t.Errorf("move %d = %+v, want a move by %v at %v", i, *m, want[i], speed)
+			}
+		}
+	}
+}
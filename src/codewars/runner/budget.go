@@ -0,0 +1,85 @@
+package runner
+
+import (
+	. "codewars"
+)
+
+// ActionBudget keeps track of how many actions a player may still make. The
+// server allows BaseActionCount actions, plus AdditionalActionCountPerControlCenter
+// for each control center the player owns, in any ActionDetectionInterval
+// consecutive ticks.
+type ActionBudget struct {
+	game     *Game
+	tick     int
+	limit    int
+	cooldown int
+	// ticks of the actions made in the current window, oldest first
+	actions []int
+}
+
+// BudgetedStrategy is implemented by strategies that want to see the
+// ActionBudget the runner maintains for them.
+type BudgetedStrategy interface {
+	Strategy
+	SetActionBudget(budget *ActionBudget)
+}
+
+func NewActionBudget(game *Game) *ActionBudget {
+	return &ActionBudget{game: game, limit: game.BaseActionCount}
+}
+
+// Update moves the budget to the tick of world. It must be called before the
+// strategy makes its move. Without a world only the cooldown is updated.
+func (b *ActionBudget) Update(me *Player, world *World) {
+	b.cooldown = me.RemainingActionCooldownTicks
+	if world == nil {
+		return
+	}
+	b.tick = world.TickIndex
+
+	controlCenters := 0
+	for _, f := range world.Facilities {
+		if f != nil && f.Type == Facility_Control_Center && f.OwnerPlayerId == me.Id {
+			controlCenters++
+		}
+	}
+	b.limit = b.game.BaseActionCount + b.game.AdditionalActionCountPerControlCenter*controlCenters
+
+	for len(b.actions) > 0 && b.actions[0] <= b.tick-b.game.ActionDetectionInterval {
+		b.actions = b.actions[1:]
+	}
+}
+
+// Record counts move against the budget unless it does nothing or the server
+// is going to ignore it.
+func (b *ActionBudget) Record(move *Move) {
+	if move == nil || move.Action == Action_None || b.cooldown > 0 {
+		return
+	}
+	b.actions = append(b.actions, b.tick)
+}
+
+func (b *ActionBudget) Limit() int {
+	return b.limit
+}
+
+// Remaining returns how many actions can be made from the current tick on
+// before the oldest one in the window expires.
+func (b *ActionBudget) Remaining() int {
+	if b.cooldown > 0 || len(b.actions) >= b.limit {
+		return 0
+	}
+	return b.limit - len(b.actions)
+}
+
+// NextFreeTick returns the index of the first tick on which an action can be
+// made, the current one if the budget is not exhausted.
+func (b *ActionBudget) NextFreeTick() int {
+	next := b.tick + b.cooldown
+	if n := len(b.actions); b.limit > 0 && n >= b.limit {
+		if t := b.actions[n-b.limit] + b.game.ActionDetectionInterval; t > next {
+			next = t
+		}
+	}
+	return next
+}
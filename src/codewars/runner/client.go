@@ -5,7 +5,8 @@ import (
 	. "codewars"
 	"encoding/binary"
 	"errors"
-	//"fmt"
+	"fmt"
+	"io"
 	"net"
 )
 
@@ -19,9 +20,9 @@ type PlayerContext struct {
 }
 
 type Client struct {
-	conn net.Conn
-	w    *bufio.Writer
-	r    *bufio.Reader
+	closers []io.Closer
+	w       *bufio.Writer
+	r       *bufio.Reader
 
 	previousPlayers    []*Player
 	previousFacilities []*Facility
@@ -30,7 +31,14 @@ type Client struct {
 	WeatherByCellXY [][]WeatherType
 
 	previousPlayerById map[int64]*Player
-	prevoiusUnitById   map[int64]interface{}
+	vehicleById        map[int64]*Vehicle
+
+	// from the game, for the facilities
+	facilityWidth, facilityHeight float64
+
+	expected MessageType
+	actual   MessageType
+	err      error
 }
 
 type MessageType int
@@ -46,43 +54,138 @@ const (
 	Message_Moves
 )
 
+var messageTypeNames = [...]string{
+	"Unknown",
+	"GameOver",
+	"AuthToken",
+	"TeamSize",
+	"ProtoVersion",
+	"GameContext",
+	"PlayerContext",
+	"Moves",
+}
+
+func (m MessageType) String() string {
+	if m < 0 || int(m) >= len(messageTypeNames) {
+		return fmt.Sprintf("MessageType(%d)", int(m))
+	}
+	return messageTypeNames[m]
+}
+
+var ErrUnexpectedMessage = errors.New("unexpected message")
+
+// ProtocolError is returned by every Read*/Write* method of Client when the
+// exchange with the server fails. Expected and Actual are the opcodes of the
+// message being processed, Field names the part of it that was being decoded
+// or encoded, and Err is either the underlying I/O error or ErrUnexpectedMessage.
+type ProtocolError struct {
+	Expected MessageType
+	Actual   MessageType
+	Field    string
+	Err      error
+}
+
+func (e *ProtocolError) Error() string {
+	if e.Err == ErrUnexpectedMessage {
+		return fmt.Sprintf("codewars: unexpected message %v while reading %s, expected %v", e.Actual, e.Field, e.Expected)
+	}
+	if e.Field == e.Expected.String() {
+		return fmt.Sprintf("codewars: %v message: %v", e.Expected, e.Err)
+	}
+	return fmt.Sprintf("codewars: %s of %v message: %v", e.Field, e.Expected, e.Err)
+}
+
+func (e *ProtocolError) Unwrap() error {
+	return e.Err
+}
+
 func NewClient(addr string) (*Client, error) {
 	conn, err := net.Dial("tcp", addr)
 	if err != nil {
 		return nil, err
 	}
-	return &Client{conn, bufio.NewWriter(conn), bufio.NewReader(conn),
-		nil, nil, nil, nil, // previous
-		make(map[int64]*Player), make(map[int64]interface{}), // previousById
-	}, nil
+	return NewClientConn(conn), nil
+}
+
+// NewClientConn returns a client talking over an already established stream
+// such as a net.Pipe, a Unix socket or a recorded session in a bytes.Buffer.
+// Close closes rw if it implements io.Closer.
+func NewClientConn(rw io.ReadWriter) *Client {
+	c := newClient(rw, rw)
+	if closer, ok := rw.(io.Closer); ok {
+		c.closers = []io.Closer{closer}
+	}
+	return c
+}
+
+// NewClientReadWriter returns a client reading server messages from r and
+// writing its own to w, e.g. os.Stdin and os.Stdout. Close closes whichever
+// of them implements io.Closer.
+func NewClientReadWriter(r io.Reader, w io.Writer) *Client {
+	c := newClient(r, w)
+	if closer, ok := r.(io.Closer); ok {
+		c.closers = append(c.closers, closer)
+	}
+	if closer, ok := w.(io.Closer); ok {
+		c.closers = append(c.closers, closer)
+	}
+	return c
+}
+
+func newClient(r io.Reader, w io.Writer) *Client {
+	return &Client{
+		w:                  bufio.NewWriter(w),
+		r:                  bufio.NewReader(r),
+		previousPlayerById: make(map[int64]*Player),
+		vehicleById:        make(map[int64]*Vehicle),
+	}
 }
 
 func (c *Client) Close() error {
-	return c.conn.Close()
+	var err error
+	for _, closer := range c.closers {
+		if e := closer.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// Err returns the first error the client ran into. Once set, the client
+// stops reading and writing and every Read*/Write* method returns it.
+func (c *Client) Err() error {
+	return c.err
 }
 
-func (c *Client) WriteToken(token string) {
+func (c *Client) WriteToken(token string) error {
 	c.writeOpcode(Message_AuthToken)
 	c.writeString(token)
 	c.flush()
+	return c.wrap("Token")
 }
 
-func (c *Client) WriteProtocolVersion(ver int) {
+func (c *Client) WriteProtocolVersion(ver int) error {
 	c.writeOpcode(Message_ProtoVersion)
 	c.writeInt(ver)
 	c.flush()
+	return c.wrap("Version")
 }
 
-func (c *Client) WriteMovesMessage(move *Move) {
+func (c *Client) WriteMovesMessage(move *Move) error {
 	c.writeOpcode(Message_Moves)
 	c.writeMove(move)
 	c.flush()
+	return c.wrap("Move")
 }
 
 func (c *Client) writeMove(move *Move) {
+	if move == nil {
+		c.writeByte(0)
+		return
+	}
 	c.writeByte(1)
 
-	c.writeByte(byte(move.Action))
+	c.writeActionType(move.Action)
 	c.writeInt(move.Group)
 	c.writeFloat64(move.Left)
 	c.writeFloat64(move.Top)
@@ -91,34 +194,107 @@ func (c *Client) writeMove(move *Move) {
 	c.writeFloat64(move.X)
 	c.writeFloat64(move.Y)
 	c.writeFloat64(move.Angle)
+	c.writeFloat64(move.Factor)
 	c.writeFloat64(move.Max_speed)
 	c.writeFloat64(move.Max_angular_speed)
-	c.writeByte(byte(move.Vehicle_type))
+	c.writeVehicleType(move.Vehicle_type)
 	c.writeInt64(move.Facility_id)
+	c.writeInt64(move.Vehicle_id)
+
+}
 
+// ReadToken, ReadProtocolVersion and ReadMovesMessage decode the messages a
+// strategy sends, as found in the client side of a capture.
+func (c *Client) ReadToken() (string, error) {
+	c.ensureMessageType(Message_AuthToken)
+	token := c.readString()
+	if err := c.wrap("Token"); err != nil {
+		return "", err
+	}
+	return token, nil
 }
 
-func (c *Client) ReadTeamSize() int {
-	c.ensureMessageType(c.readByte(), Message_TeamSize)
-	return c.readInt()
+func (c *Client) ReadProtocolVersion() (int, error) {
+	c.ensureMessageType(Message_ProtoVersion)
+	ver := c.readInt()
+	if err := c.wrap("Version"); err != nil {
+		return 0, err
+	}
+	return ver, nil
 }
 
-func (c *Client) ReadPlayerContext() *PlayerContext {
-	opcode := c.readByte()
-	if opcode == byte(Message_GameOver) {
+func (c *Client) ReadMovesMessage() (*Move, error) {
+	move := c.readMovesMessage()
+	if err := c.wrap("Move"); err != nil {
+		return nil, err
+	}
+	return move, nil
+}
+
+func (c *Client) readMovesMessage() *Move {
+	if !c.ensureMessageType(Message_Moves) || !c.readBool() {
 		return nil
 	}
-	c.ensureMessageType(opcode, Message_PlayerContext)
-	if !c.readBool() {
+	return &Move{
+		Action:            c.readActionType(),
+		Group:             c.readInt(),
+		Left:              c.readFloat64(),
+		Top:               c.readFloat64(),
+		Right:             c.readFloat64(),
+		Bottom:            c.readFloat64(),
+		X:                 c.readFloat64(),
+		Y:                 c.readFloat64(),
+		Angle:             c.readFloat64(),
+		Factor:            c.readFloat64(),
+		Max_speed:         c.readFloat64(),
+		Max_angular_speed: c.readFloat64(),
+		Vehicle_type:      c.readVehicleType(),
+		Facility_id:       c.readInt64(),
+		Vehicle_id:        c.readInt64(),
+	}
+}
+
+func (c *Client) ReadTeamSize() (int, error) {
+	c.ensureMessageType(Message_TeamSize)
+	n := c.readInt()
+	if err := c.wrap("TeamSize"); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// ReadPlayerContext returns nil without an error once the server reports the game is over.
+func (c *Client) ReadPlayerContext() (*PlayerContext, error) {
+	ctx := c.readPlayerContext()
+	if err := c.wrap("PlayerContext"); err != nil {
+		return nil, err
+	}
+	return ctx, nil
+}
+
+func (c *Client) readPlayerContext() *PlayerContext {
+	if c.readOpcode(Message_PlayerContext) == Message_GameOver {
+		return nil
+	}
+	if !c.checkMessageType() || !c.readBool() {
 		return nil
 	}
 	return &PlayerContext{
-		Player: c.ReadPlayer(),
-		World:  c.ReadWorld(),
+		Player: c.readPlayer(),
+		World:  c.readWorld(),
 	}
 }
 
-func (c *Client) ReadWorld() *World {
+func (c *Client) ReadWorld() (*World, error) {
+	w := c.readWorld()
+	if err := c.wrap("World"); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (c *Client) readWorld() *World {
+	defer c.wrap("World")
 	if !c.readBool() {
 		return nil
 	}
@@ -127,35 +303,62 @@ func (c *Client) ReadWorld() *World {
 		TickCount:     c.readInt(),
 		Width:         c.readFloat64(),
 		Height:        c.readFloat64(),
-		Players:       c.ReadPlayers(),
-		NewVehicles:   c.ReadVehicles(),
-		VehicleUpdate: c.ReadVehicleUpdates(),
+		Players:       c.readPlayers(),
+		NewVehicles:   c.readVehicles(),
+		VehicleUpdate: c.readVehicleUpdates(),
 	}
+	// the server sends the maps with the first world only
 	if c.TerrainByCellXY == nil {
-		w.TerrainByCellXY = c.ReadTerrainByCellXY()
+		c.readTerrainByCellXY()
 	}
 	if c.WeatherByCellXY == nil {
-		w.WeatherByCellXY = c.ReadWeatherByCellXY()
+		c.readWeatherByCellXY()
 	}
-	w.Facilities = c.ReadFacilities()
+	w.TerrainByCellXY = c.TerrainByCellXY
+	w.WeatherByCellXY = c.WeatherByCellXY
+	w.Facilities = c.readFacilities()
+	if c.err != nil {
+		return nil
+	}
+
+	UpdateVehicles(c.vehicleById, w.NewVehicles, w.VehicleUpdate)
+	w.VehicleById = c.vehicleById
 
 	return &w
 }
 
-func (c *Client) ReadPlayers() []*Player {
+func (c *Client) ReadPlayers() ([]*Player, error) {
+	p := c.readPlayers()
+	if err := c.wrap("Players"); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (c *Client) readPlayers() []*Player {
+	defer c.wrap("Players")
 	l := c.readInt()
 	if l < 0 {
 		return c.previousPlayers
 	}
 	r := make([]*Player, l)
 	for i := range r {
-		r[i] = c.ReadPlayer()
+		r[i] = c.readPlayer()
 	}
 	c.previousPlayers = r
 	return r
 }
 
-func (c *Client) ReadPlayer() *Player {
+func (c *Client) ReadPlayer() (*Player, error) {
+	p := c.readPlayer()
+	if err := c.wrap("Player"); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (c *Client) readPlayer() *Player {
+	defer c.wrap("Player")
 	switch c.readByte() {
 	case 0:
 		return nil
@@ -166,25 +369,55 @@ func (c *Client) ReadPlayer() *Player {
 			Id: c.readInt64(),
 			Me: c.readBool(),
 			//Name:            c.readString(),
-			StrategyCrashed: c.readBool(),
-			Score:           c.readInt(),
+			StrategyCrashed:              c.readBool(),
+			Score:                        c.readInt(),
 			RemainingActionCooldownTicks: c.readInt(),
+
+			RemainingNuclearStrikeCooldownTicks: c.readInt(),
+			NextNuclearStrikeVehicleId:          c.readInt64(),
+			NextNuclearStrikeTickIndex:          c.readInt(),
+			NextNuclearStrikeX:                  c.readFloat64(),
+			NextNuclearStrikeY:                  c.readFloat64(),
+		}
+		if c.err != nil {
+			return nil
 		}
 		c.previousPlayerById[p.Id] = p
 		return p
 	}
 }
 
-func (c *Client) ReadVehicles() []*Vehicle {
+func (c *Client) ReadVehicles() ([]*Vehicle, error) {
+	v := c.readVehicles()
+	if err := c.wrap("Vehicles"); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (c *Client) readVehicles() []*Vehicle {
+	defer c.wrap("Vehicles")
 	l := c.readInt()
+	if l < 0 {
+		return nil
+	}
 	r := make([]*Vehicle, l)
 	for i := range r {
-		r[i] = c.ReadVehicle()
+		r[i] = c.readVehicle()
 	}
 	return r
 }
 
-func (c *Client) ReadVehicle() *Vehicle {
+func (c *Client) ReadVehicle() (*Vehicle, error) {
+	v := c.readVehicle()
+	if err := c.wrap("Vehicle"); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (c *Client) readVehicle() *Vehicle {
+	defer c.wrap("Vehicle")
 	if !c.readBool() {
 		return nil
 	}
@@ -206,23 +439,44 @@ func (c *Client) ReadVehicle() *Vehicle {
 		AerialDefence:                c.readInt(),
 		AttackCooldownTicks:          c.readInt(),
 		RemainingAttackCooldownTicks: c.readInt(),
-		VehicleType:                  VehicleType(c.readByte()),
+		VehicleType:                  c.readVehicleType(),
 		Aerial:                       c.readBool(),
 		Selected:                     c.readBool(),
 		Groups:                       c.readIntArray(),
 	}
 }
 
-func (c *Client) ReadVehicleUpdates() []*VehicleUpdate {
+func (c *Client) ReadVehicleUpdates() ([]*VehicleUpdate, error) {
+	u := c.readVehicleUpdates()
+	if err := c.wrap("VehicleUpdates"); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+func (c *Client) readVehicleUpdates() []*VehicleUpdate {
+	defer c.wrap("VehicleUpdates")
 	l := c.readInt()
+	if l < 0 {
+		return nil
+	}
 	r := make([]*VehicleUpdate, l)
 	for i := range r {
-		r[i] = c.ReadVehicleUpdate()
+		r[i] = c.readVehicleUpdate()
 	}
 	return r
 }
 
-func (c *Client) ReadVehicleUpdate() *VehicleUpdate {
+func (c *Client) ReadVehicleUpdate() (*VehicleUpdate, error) {
+	u := c.readVehicleUpdate()
+	if err := c.wrap("VehicleUpdate"); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+func (c *Client) readVehicleUpdate() *VehicleUpdate {
+	defer c.wrap("VehicleUpdate")
 	if !c.readBool() {
 		return nil
 	}
@@ -239,69 +493,125 @@ func (c *Client) ReadVehicleUpdate() *VehicleUpdate {
 	}
 }
 
-func (c *Client) ReadTerrainByCellXY() [][]TerrainType {
+func (c *Client) ReadTerrainByCellXY() ([][]TerrainType, error) {
+	t := c.readTerrainByCellXY()
+	if err := c.wrap("TerrainByCellXY"); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (c *Client) readTerrainByCellXY() [][]TerrainType {
+	defer c.wrap("TerrainByCellXY")
 	countX := c.readInt()
+	if countX < 0 {
+		return nil
+	}
 	rX := make([][]TerrainType, countX)
 	for i := range rX {
 
 		countY := c.readInt()
+		if countY < 0 {
+			continue
+		}
 
 		rY := make([]TerrainType, countY)
 		for i := range rY {
-			rY[i] = TerrainType(c.readByte())
+			rY[i] = c.readTerrainType()
 		}
 		rX[i] = rY
 	}
+	if c.err != nil {
+		return nil
+	}
 
 	c.TerrainByCellXY = rX
 	return c.TerrainByCellXY
 }
 
-func (c *Client) ReadWeatherByCellXY() [][]WeatherType {
+func (c *Client) ReadWeatherByCellXY() ([][]WeatherType, error) {
+	w := c.readWeatherByCellXY()
+	if err := c.wrap("WeatherByCellXY"); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (c *Client) readWeatherByCellXY() [][]WeatherType {
+	defer c.wrap("WeatherByCellXY")
 	countX := c.readInt()
+	if countX < 0 {
+		return nil
+	}
 	rX := make([][]WeatherType, countX)
 	for i := range rX {
 
 		countY := c.readInt()
+		if countY < 0 {
+			continue
+		}
 
 		rY := make([]WeatherType, countY)
 		for i := range rY {
-			rY[i] = WeatherType(c.readByte())
+			rY[i] = c.readWeatherType()
 		}
 		rX[i] = rY
 	}
+	if c.err != nil {
+		return nil
+	}
 
 	c.WeatherByCellXY = rX
 
 	return c.WeatherByCellXY
 }
 
-func (c *Client) ReadFacilities() []*Facility {
+func (c *Client) ReadFacilities() ([]*Facility, error) {
+	f := c.readFacilities()
+	if err := c.wrap("Facilities"); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (c *Client) readFacilities() []*Facility {
+	defer c.wrap("Facilities")
 	l := c.readInt()
 	if l < 0 {
 		return c.previousFacilities
 	}
 	f := make([]*Facility, l)
 	for i := range f {
-		f[i] = c.ReadFacility()
+		f[i] = c.readFacility()
 	}
 	c.previousFacilities = f
 	return f
 }
 
-func (c *Client) ReadFacility() *Facility {
+func (c *Client) ReadFacility() (*Facility, error) {
+	f := c.readFacility()
+	if err := c.wrap("Facility"); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (c *Client) readFacility() *Facility {
+	defer c.wrap("Facility")
 	if !c.readBool() {
 		return nil
 	}
 	return &Facility{
 		Id:                 c.readInt64(),
-		Type:               FacilityType(c.readByte()),
+		Type:               c.readFacilityType(),
 		OwnerPlayerId:      c.readInt64(),
 		Left:               c.readFloat64(),
 		Top:                c.readFloat64(),
 		CapturePoints:      c.readFloat64(),
-		VehicleType:        VehicleType(c.readByte()),
+		VehicleType:        c.readVehicleType(),
 		ProductionProgress: c.readInt(),
+		Width:              c.facilityWidth,
+		Height:             c.facilityHeight,
 	}
 }
 
@@ -316,9 +626,19 @@ func (c *Client) readCircularUnit() CircularUnit {
 	}
 }
 
-func (c *Client) ReadGameContext() *Game {
-	c.ensureMessageType(c.readByte(), Message_GameContext)
-	if !c.readBool() {
+func (c *Client) ReadGameContext() (*Game, error) {
+	g := c.readGameContext()
+	if err := c.wrap("Game"); err != nil {
+		return nil, err
+	}
+	if g != nil {
+		c.facilityWidth, c.facilityHeight = g.FacilityWidth, g.FacilityHeight
+	}
+	return g, nil
+}
+
+func (c *Client) readGameContext() *Game {
+	if !c.ensureMessageType(Message_GameContext) || !c.readBool() {
 		return nil
 	}
 	return &Game{
@@ -411,11 +731,20 @@ func (c *Client) ReadGameContext() *Game {
 		FacilityCapturePointsPerVehiclePerTick: c.readFloat64(),
 		FacilityWidth:                          c.readFloat64(),
 		FacilityHeight:                         c.readFloat64(),
+
+		BaseTacticalNuclearStrikeCooldown:                     c.readInt(),
+		TacticalNuclearStrikeCooldownDecreasePerControlCenter: c.readInt(),
+		MaxTacticalNuclearStrikeDamage:                        c.readFloat64(),
+		TacticalNuclearStrikeRadius:                           c.readFloat64(),
+		TacticalNuclearStrikeDelay:                            c.readInt(),
 	}
 }
 
 func (c *Client) readIntArray() []int {
 	count := c.readInt()
+	if count < 0 {
+		return nil
+	}
 	r := make([]int, count)
 	for i := range r {
 		r[i] = c.readInt()
@@ -425,6 +754,9 @@ func (c *Client) readIntArray() []int {
 
 func (c *Client) readIntArray2D() [][]int {
 	count := c.readInt()
+	if count < 0 {
+		return nil
+	}
 	r := make([][]int, count)
 	for i := range r {
 		r[i] = c.readIntArray()
@@ -432,27 +764,30 @@ func (c *Client) readIntArray2D() [][]int {
 	return r
 }
 
+func (c *Client) read(v interface{}) {
+	if c.err != nil {
+		return
+	}
+	if err := binary.Read(c.r, Order, v); err != nil {
+		c.err = err
+	}
+}
+
 func (c *Client) readInt() int {
 	var v int32
-	if err := binary.Read(c.r, Order, &v); err != nil {
-		panic(err)
-	}
+	c.read(&v)
 	return int(v)
 }
 
 func (c *Client) readInt64() int64 {
 	var v int64
-	if err := binary.Read(c.r, Order, &v); err != nil {
-		panic(err)
-	}
+	c.read(&v)
 	return v
 }
 
 func (c *Client) readFloat64() float64 {
 	var v float64
-	if err := binary.Read(c.r, Order, &v); err != nil {
-		panic(err)
-	}
+	c.read(&v)
 	return v
 }
 
@@ -461,15 +796,22 @@ func (c *Client) readBool() bool {
 }
 
 func (c *Client) readByte() byte {
+	if c.err != nil {
+		return 0
+	}
 	b, err := c.r.ReadByte()
 	if err != nil {
-		panic(err)
+		c.err = err
+		return 0
 	}
 	return b
 }
 
 func (c *Client) readBytes() []byte {
 	l := c.readInt()
+	if l < 0 {
+		return nil
+	}
 	r := make([]byte, l)
 	for i := range r {
 		r[i] = c.readByte()
@@ -481,56 +823,177 @@ func (c *Client) readString() string {
 	return string(c.readBytes())
 }
 
-func (c *Client) ensureMessageType(v byte, m MessageType) {
-	if v != byte(m) {
-		panic(errors.New("unexpected message"))
+func (c *Client) readActionType() ActionType {
+	t, err := DecodeActionType(c.readByte())
+	c.fail(err)
+	return t
+}
+
+func (c *Client) readVehicleType() VehicleType {
+	t, err := DecodeVehicleType(c.readByte())
+	c.fail(err)
+	return t
+}
+
+func (c *Client) readFacilityType() FacilityType {
+	t, err := DecodeFacilityType(c.readByte())
+	c.fail(err)
+	return t
+}
+
+func (c *Client) readTerrainType() TerrainType {
+	t, err := DecodeTerrainType(c.readByte())
+	c.fail(err)
+	return t
+}
+
+func (c *Client) readWeatherType() WeatherType {
+	t, err := DecodeWeatherType(c.readByte())
+	c.fail(err)
+	return t
+}
+
+func (c *Client) readOpcode(expected MessageType) MessageType {
+	c.expected = expected
+	c.actual = MessageType(c.readByte())
+	return c.actual
+}
+
+func (c *Client) checkMessageType() bool {
+	if c.err == nil && c.actual != c.expected {
+		c.err = &ProtocolError{c.expected, c.actual, "opcode", ErrUnexpectedMessage}
+	}
+	return c.err == nil
+}
+
+func (c *Client) ensureMessageType(m MessageType) bool {
+	c.readOpcode(m)
+	return c.checkMessageType()
+}
+
+func (c *Client) fail(err error) {
+	if c.err == nil && err != nil {
+		c.err = err
 	}
 }
 
+// wrap turns a bare I/O error into a ProtocolError naming the field being
+// processed. Errors that are already wrapped keep the innermost field.
+func (c *Client) wrap(field string) error {
+	if c.err == nil {
+		return nil
+	}
+	if _, ok := c.err.(*ProtocolError); !ok {
+		c.err = &ProtocolError{c.expected, c.actual, field, c.err}
+	}
+	return c.err
+}
+
 func (c *Client) writeOpcode(m MessageType) {
+	c.expected, c.actual = m, m
 	c.writeByte(byte(m))
 }
 
-func (c *Client) writeInt(v int) {
-	if err := binary.Write(c.w, Order, int32(v)); err != nil {
-		panic(err)
+func (c *Client) write(v interface{}) {
+	if c.err != nil {
+		return
+	}
+	if err := binary.Write(c.w, Order, v); err != nil {
+		c.err = err
 	}
 }
 
+func (c *Client) writeInt(v int) {
+	c.write(int32(v))
+}
+
 func (c *Client) writeFloat64(v float64) {
-	if err := binary.Write(c.w, Order, v); err != nil {
-		panic(err)
-	}
+	c.write(v)
 }
 
 func (c *Client) writeInt64(v int64) {
-	if err := binary.Write(c.w, Order, v); err != nil {
-		panic(err)
-	}
+	c.write(v)
 }
 
 func (c *Client) writeByte(v byte) {
+	if c.err != nil {
+		return
+	}
 	if err := c.w.WriteByte(v); err != nil {
-		panic(err)
+		c.err = err
+	}
+}
+
+func (c *Client) writeBool(v bool) {
+	if v {
+		c.writeByte(1)
+	} else {
+		c.writeByte(0)
 	}
 }
 
+func (c *Client) writeIntArray(v []int) {
+	c.writeInt(len(v))
+	for _, i := range v {
+		c.writeInt(i)
+	}
+}
+
+func (c *Client) writeActionType(t ActionType) {
+	b, err := t.Encode()
+	c.fail(err)
+	c.writeByte(b)
+}
+
+func (c *Client) writeVehicleType(t VehicleType) {
+	b, err := t.Encode()
+	c.fail(err)
+	c.writeByte(b)
+}
+
+func (c *Client) writeFacilityType(t FacilityType) {
+	b, err := t.Encode()
+	c.fail(err)
+	c.writeByte(b)
+}
+
+func (c *Client) writeTerrainType(t TerrainType) {
+	b, err := t.Encode()
+	c.fail(err)
+	c.writeByte(b)
+}
+
+func (c *Client) writeWeatherType(t WeatherType) {
+	b, err := t.Encode()
+	c.fail(err)
+	c.writeByte(b)
+}
+
 func (c *Client) writeBytes(v []byte) {
 	c.writeInt(len(v))
+	if c.err != nil {
+		return
+	}
 	if _, err := c.w.Write(v); err != nil {
-		panic(err)
+		c.err = err
 	}
 }
 
 func (c *Client) writeString(v string) {
 	c.writeInt(len(v))
+	if c.err != nil {
+		return
+	}
 	if _, err := c.w.WriteString(v); err != nil {
-		panic(err)
+		c.err = err
 	}
 }
 
 func (c *Client) flush() {
+	if c.err != nil {
+		return
+	}
 	if err := c.w.Flush(); err != nil {
-		panic(err)
+		c.err = err
 	}
 }
@@ -0,0 +1,71 @@
+package runner
+
+import (
+	"bytes"
+	. "codewars"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestTruncatedMessage(t *testing.T) {
+	fixture := gameFixture(testGame())
+	// Cut in the middle of the last int32.
+	c := NewClientConn(bytes.NewBuffer(fixture[:len(fixture)-2]))
+
+	g, err := c.ReadGameContext()
+	if g != nil || !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("ReadGameContext() = %v, %v, want an unexpected EOF", g, err)
+	}
+	var pe *ProtocolError
+	if !errors.As(err, &pe) || pe.Expected != Message_GameContext || pe.Actual != Message_GameContext || pe.Field != "Game" {
+		t.Errorf("error = %#v, want a ProtocolError on the Game of a GameContext", err)
+	}
+	// The error sticks.
+	if _, again := c.ReadPlayerContext(); again != err {
+		t.Errorf("next read error = %v, want %v", again, err)
+	}
+	if c.Err() != err {
+		t.Errorf("Err() = %v, want %v", c.Err(), err)
+	}
+}
+
+func TestTruncatedField(t *testing.T) {
+	var buf bytes.Buffer
+	server := NewServerConn(&buf)
+	server.WriteGameContext(NewGame())
+	contexts := testContexts(NewGame())
+	server.WritePlayerContext(contexts[0])
+	// Cut within the CapturePoints of the last facility.
+	c := NewClientConn(bytes.NewBuffer(buf.Bytes()[:buf.Len()-10]))
+	if _, err := c.ReadGameContext(); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, err := c.ReadPlayerContext()
+	if ctx != nil || !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("ReadPlayerContext() = %v, %v, want an unexpected EOF", ctx, err)
+	}
+	var pe *ProtocolError
+	if !errors.As(err, &pe) || pe.Expected != Message_PlayerContext || pe.Field != "Facility" {
+		t.Errorf("error = %#v, want a ProtocolError on a Facility of a PlayerContext", err)
+	}
+}
+
+func TestUnexpectedMessage(t *testing.T) {
+	var buf bytes.Buffer
+	NewServerConn(&buf).WriteTeamSize(1)
+	c := NewClientConn(&buf)
+
+	g, err := c.ReadGameContext()
+	if g != nil || !errors.Is(err, ErrUnexpectedMessage) {
+		t.Fatalf("ReadGameContext() = %v, %v, want ErrUnexpectedMessage", g, err)
+	}
+	var pe *ProtocolError
+	if !errors.As(err, &pe) {
+		t.Fatalf("error = %#v, want a ProtocolError", err)
+	}
+	if pe.Expected != Message_GameContext || pe.Actual != Message_TeamSize || pe.Field != "opcode" {
+		t.Errorf("error = %+v, want GameContext expected, TeamSize read, on the opcode", *pe)
+	}
+}
@@ -0,0 +1,113 @@
+package runner
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// A capture file starts with CaptureMagic followed by records. Every record
+// is a RecordKind byte, a little-endian int32 payload length and the payload.
+const CaptureMagic = "CWCAP001"
+
+type RecordKind byte
+
+const (
+	// bytes read from the server
+	Record_Server RecordKind = 'S'
+	// bytes written to the server
+	Record_Client RecordKind = 'C'
+	// int32 tick index, written once the move of that tick is sent; the client
+	// bytes before it belong to that tick or earlier, but server bytes of later
+	// ticks may precede it since the client buffers its reads ahead
+	Record_Tick RecordKind = 'T'
+)
+
+var ErrBadCapture = errors.New("codewars: not a capture file")
+
+// Recorder tees the byte stream exchanged with the server into a capture
+// file. A failure to record never breaks the session: the first error stops
+// recording and is returned by Close. Recorder is not safe for concurrent use.
+type Recorder struct {
+	w      *bufio.Writer
+	closer io.Closer
+	err    error
+}
+
+// NewRecorder starts a capture in w. Close closes w if it implements io.Closer.
+func NewRecorder(w io.Writer) *Recorder {
+	r := &Recorder{w: bufio.NewWriter(w)}
+	if closer, ok := w.(io.Closer); ok {
+		r.closer = closer
+	}
+	_, r.err = r.w.WriteString(CaptureMagic)
+	return r
+}
+
+// Reader returns a reader that records everything read from src.
+func (r *Recorder) Reader(src io.Reader) io.Reader {
+	return &recordingReader{r, src}
+}
+
+// Writer returns a writer that records everything written to dst.
+func (r *Recorder) Writer(dst io.Writer) io.Writer {
+	return &recordingWriter{r, dst}
+}
+
+// Tick marks the end of the tick with the given index.
+func (r *Recorder) Tick(index int) {
+	var b [4]byte
+	Order.PutUint32(b[:], uint32(int32(index)))
+	r.record(Record_Tick, b[:])
+	if r.err == nil {
+		r.err = r.w.Flush()
+	}
+}
+
+func (r *Recorder) Close() error {
+	if r.err == nil {
+		r.err = r.w.Flush()
+	}
+	if r.closer != nil {
+		if err := r.closer.Close(); err != nil && r.err == nil {
+			r.err = err
+		}
+	}
+	return r.err
+}
+
+func (r *Recorder) record(kind RecordKind, p []byte) {
+	if r.err != nil || len(p) == 0 && kind != Record_Tick {
+		return
+	}
+	if r.err = r.w.WriteByte(byte(kind)); r.err != nil {
+		return
+	}
+	if r.err = binary.Write(r.w, Order, int32(len(p))); r.err != nil {
+		return
+	}
+	_, r.err = r.w.Write(p)
+}
+
+type recordingReader struct {
+	rec *Recorder
+	src io.Reader
+}
+
+func (r *recordingReader) Read(p []byte) (int, error) {
+	n, err := r.src.Read(p)
+	r.rec.record(Record_Server, p[:n])
+	return n, err
+}
+
+type recordingWriter struct {
+	rec *Recorder
+	dst io.Writer
+}
+
+func (w *recordingWriter) Write(p []byte) (int, error) {
+	n, err := w.dst.Write(p)
+	w.rec.record(Record_Client, p[:n])
+	return n, err
+}
@@ -0,0 +1,173 @@
+package runner
+
+import (
+	"bufio"
+	"bytes"
+	. "codewars"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+)
+
+// Capture is a session recorded by Recorder split back into the streams sent
+// by each side.
+type Capture struct {
+	Server []byte
+	Client []byte
+	// indices of the ticks marked in the capture, in order
+	Ticks []int
+}
+
+func ReadCapture(r io.Reader) (*Capture, error) {
+	br := bufio.NewReader(r)
+	magic := make([]byte, len(CaptureMagic))
+	if _, err := io.ReadFull(br, magic); err != nil || string(magic) != CaptureMagic {
+		return nil, ErrBadCapture
+	}
+
+	var server, client bytes.Buffer
+	capture := &Capture{}
+	for {
+		kind, err := br.ReadByte()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		var l int32
+		if err := binary.Read(br, Order, &l); err != nil {
+			return nil, err
+		}
+		if l < 0 {
+			return nil, ErrBadCapture
+		}
+		var dst io.Writer
+		switch RecordKind(kind) {
+		case Record_Server:
+			dst = &server
+		case Record_Client:
+			dst = &client
+		case Record_Tick:
+			var tick int32
+			if l != 4 || binary.Read(br, Order, &tick) != nil {
+				return nil, ErrBadCapture
+			}
+			capture.Ticks = append(capture.Ticks, int(tick))
+			continue
+		default:
+			return nil, ErrBadCapture
+		}
+		if _, err := io.CopyN(dst, br, int64(l)); err != nil {
+			return nil, err
+		}
+	}
+	capture.Server = server.Bytes()
+	capture.Client = client.Bytes()
+	return capture, nil
+}
+
+// DivergenceError reports the first tick on which a replayed strategy
+// produced a Move different from the recorded one.
+type DivergenceError struct {
+	TickIndex int
+	Recorded  *Move
+	Actual    *Move
+}
+
+func (e *DivergenceError) Error() string {
+	return fmt.Sprintf("codewars: tick %d diverged: recorded %+v, got %+v", e.TickIndex, e.Recorded, e.Actual)
+}
+
+// Replay feeds the game and every world recorded in capture to a fresh
+// strategy and compares each Move it makes with the recorded one. It returns
+// the number of ticks that matched and a *DivergenceError for the first one
+// that did not. A capture cut short between messages replays up to the cut.
+func Replay(capture *Capture, factory StrategyFactory) (int, error) {
+	return New("", "", factory).Replay(capture)
+}
+
+// Replay is like the Replay function but handles invalid moves the way Run
+// does, so that it reproduces sessions played with the same policy.
+func (r *Runner) Replay(capture *Capture) (int, error) {
+	server := NewClientReadWriter(bytes.NewReader(capture.Server), ioutil.Discard)
+	client := NewClientReadWriter(bytes.NewReader(capture.Client), ioutil.Discard)
+
+	if _, err := client.ReadToken(); err != nil {
+		return 0, err
+	}
+	if _, err := client.ReadProtocolVersion(); err != nil {
+		return 0, err
+	}
+	if _, err := server.ReadTeamSize(); err != nil {
+		return 0, err
+	}
+	game, err := server.ReadGameContext()
+	if err != nil {
+		return 0, err
+	}
+
+	strategy := r.factory()
+	budget := newActionBudget(strategy, game)
+
+	ticks := 0
+	for {
+		playerContext, err := server.ReadPlayerContext()
+		if err != nil {
+			return ticks, endOfCapture(err)
+		}
+		if playerContext == nil || playerContext.Player == nil {
+			return ticks, nil
+		}
+		recorded, err := client.ReadMovesMessage()
+		if err != nil {
+			return ticks, endOfCapture(err)
+		}
+
+		move := NewMove()
+		budget.Update(playerContext.Player, playerContext.World)
+		strategy.Move(playerContext.Player, playerContext.World, game, move)
+		if move, err = r.checkMove(move, playerContext.Player, playerContext.World, game); err != nil {
+			return ticks, err
+		}
+		budget.Record(move)
+
+		if recorded == nil || !sameMove(move, recorded) {
+			return ticks, &DivergenceError{playerContext.World.TickIndex, recorded, move}
+		}
+		ticks++
+	}
+}
+
+// sameMove reports whether a and b are the same move. Unlike a == b it takes
+// NaN for equal to NaN, as the server gets the same bytes for both.
+func sameMove(a, b *Move) bool {
+	same := func(x, y float64) bool {
+		return x == y || math.IsNaN(x) && math.IsNaN(y)
+	}
+	return a.Action == b.Action &&
+		a.Group == b.Group &&
+		same(a.Left, b.Left) &&
+		same(a.Top, b.Top) &&
+		same(a.Right, b.Right) &&
+		same(a.Bottom, b.Bottom) &&
+		same(a.X, b.X) &&
+		same(a.Y, b.Y) &&
+		same(a.Angle, b.Angle) &&
+		same(a.Factor, b.Factor) &&
+		same(a.Max_speed, b.Max_speed) &&
+		same(a.Max_angular_speed, b.Max_angular_speed) &&
+		a.Vehicle_type == b.Vehicle_type &&
+		a.Facility_id == b.Facility_id &&
+		a.Vehicle_id == b.Vehicle_id
+}
+
+func endOfCapture(err error) error {
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	return err
+}
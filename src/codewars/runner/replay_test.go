@@ -0,0 +1,97 @@
+package runner
+
+import (
+	"bytes"
+	. "codewars"
+	"errors"
+	"math"
+	"net"
+	"testing"
+)
+
+// nanStrategy scales everything by factor with a NaN speed limit.
+type nanStrategy struct {
+	factor float64
+}
+
+func (s *nanStrategy) Move(me *Player, world *World, game *Game, move *Move) {
+	move.Action = Action_Scale
+	move.X, move.Y = 512, 512
+	move.Factor = s.factor
+	move.Max_speed = math.NaN()
+}
+
+// record plays the test contexts against a strategy and returns the capture.
+func record(t *testing.T, strategy Strategy) *Capture {
+	strategyEnd, serverEnd := net.Pipe()
+	defer strategyEnd.Close()
+	go func() {
+		s := NewServerConn(serverEnd)
+		defer s.Close()
+		s.ReadToken()
+		s.ReadProtocolVersion()
+		s.WriteTeamSize(1)
+		g := NewGame()
+		s.WriteGameContext(g)
+		for _, ctx := range testContexts(g) {
+			s.WritePlayerContext(ctx)
+			s.ReadMovesMessage()
+		}
+		s.WriteGameOver()
+	}()
+
+	var buf bytes.Buffer
+	rec := NewRecorder(&buf)
+	r := New("", "token", func() Strategy { return strategy })
+	r.SetRecorder(rec)
+	if err := r.RunClient(NewClientReadWriter(rec.Reader(strategyEnd), rec.Writer(strategyEnd))); err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+	capture, err := ReadCapture(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return capture
+}
+
+func TestReplayNaN(t *testing.T) {
+	capture := record(t, &nanStrategy{factor: 0.5})
+	if want := []int{0, 1, 2}; len(capture.Ticks) != len(want) || capture.Ticks[2] != 2 {
+		t.Fatalf("ticks = %v, want %v", capture.Ticks, want)
+	}
+
+	ticks, err := Replay(capture, func() Strategy { return &nanStrategy{factor: 0.5} })
+	if err != nil || ticks != 3 {
+		t.Fatalf("Replay() = %d, %v, want 3, nil", ticks, err)
+	}
+
+	ticks, err = Replay(capture, func() Strategy { return &nanStrategy{factor: 2} })
+	var de *DivergenceError
+	if !errors.As(err, &de) || ticks != 0 {
+		t.Fatalf("Replay() of another strategy = %d, %v, want a divergence on the first tick", ticks, err)
+	}
+	if de.TickIndex != 0 || de.Recorded.Factor != 0.5 || de.Actual.Factor != 2 {
+		t.Errorf("divergence = %v", de)
+	}
+}
+
+func TestSameMove(t *testing.T) {
+	a := NewMove()
+	a.Max_speed = math.NaN()
+	b := *a
+	if !sameMove(a, &b) {
+		t.Error("NaN speed limits differ")
+	}
+	b.Max_speed = 0
+	if sameMove(a, &b) || sameMove(&b, a) {
+		t.Error("NaN and 0 speed limits are the same")
+	}
+	b = *a
+	b.Vehicle_id = 1
+	if sameMove(a, &b) {
+		t.Error("moves of different vehicles are the same")
+	}
+}
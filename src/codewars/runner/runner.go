@@ -3,51 +3,155 @@ package runner
 import (
 	. "codewars"
 	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
 )
 
 const Version int = 1
 
+var (
+	recordPath   = flag.String("record", "", "write the raw session with the server to this capture file")
+	replayPath   = flag.String("replay", "", "replay this capture file against the strategy instead of connecting")
+	invalidMoves = flag.String("invalid-moves", "send", "what to do with invalid moves: send, drop, log or fail")
+)
+
+// InvalidMovePolicy tells the runner what to do with a move that fails Move.Validate.
+type InvalidMovePolicy int
+
+const (
+	// send it anyway
+	InvalidMove_Send InvalidMovePolicy = iota
+	// send an empty move instead
+	InvalidMove_Drop
+	// log the error and send an empty move instead
+	InvalidMove_Log
+	// stop and return the error from Run
+	InvalidMove_Fail
+)
+
+var invalidMovePolicies = map[string]InvalidMovePolicy{
+	"send": InvalidMove_Send,
+	"drop": InvalidMove_Drop,
+	"log":  InvalidMove_Log,
+	"fail": InvalidMove_Fail,
+}
+
 type Runner struct {
-	addr    string
-	token   string
-	factory StrategyFactory
+	addr         string
+	token        string
+	factory      StrategyFactory
+	recorder     *Recorder
+	invalidMoves InvalidMovePolicy
 }
 
 type StrategyFactory func() Strategy
 
 func Start(factory StrategyFactory) {
 	flag.Parse()
+	policy, ok := invalidMovePolicies[*invalidMoves]
+	if !ok {
+		log.Fatalf("unknown -invalid-moves value %q", *invalidMoves)
+	}
+	if *replayPath != "" {
+		replay(*replayPath, factory, policy)
+		return
+	}
 	args := flag.Args()
 	if len(args) != 3 {
 		args = []string{"127.0.0.1", "31001", "0000000000000000"}
 
 	}
 	r := New(args[0]+":"+args[1], args[2], factory)
-	if err := r.Run(); err != nil {
-		panic(err)
+	r.SetInvalidMovePolicy(policy)
+	if *recordPath != "" {
+		f, err := os.Create(*recordPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		r.SetRecorder(NewRecorder(f))
+	}
+	err := r.Run()
+	if r.recorder != nil {
+		if rerr := r.recorder.Close(); rerr != nil {
+			log.Print(rerr)
+		}
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func replay(path string, factory StrategyFactory, policy InvalidMovePolicy) {
+	f, err := os.Open(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	capture, err := ReadCapture(f)
+	f.Close()
+	if err != nil {
+		log.Fatal(err)
+	}
+	r := New("", "", factory)
+	r.SetInvalidMovePolicy(policy)
+	ticks, err := r.Replay(capture)
+	if err != nil {
+		log.Fatal(err)
 	}
+	log.Printf("replayed %d ticks without divergence", ticks)
 }
 
 func New(addr, token string, factory StrategyFactory) *Runner {
-	return &Runner{addr, token, factory}
+	return &Runner{addr: addr, token: token, factory: factory}
+}
+
+// SetRecorder makes Run capture the session with the server into rec. The
+// caller is responsible for closing rec once Run returns.
+func (r *Runner) SetRecorder(rec *Recorder) {
+	r.recorder = rec
+}
+
+func (r *Runner) SetInvalidMovePolicy(policy InvalidMovePolicy) {
+	r.invalidMoves = policy
 }
 
 func (r *Runner) Run() error {
-	client, err := NewClient(r.addr)
+	conn, err := net.Dial("tcp", r.addr)
 	if err != nil {
 		return err
 	}
-	defer client.Close()
+	client := NewClientConn(conn)
+	if r.recorder != nil {
+		client = NewClientReadWriter(r.recorder.Reader(conn), r.recorder.Writer(conn))
+	}
+	defer conn.Close()
 
-	client.WriteToken(r.token)
-	client.WriteProtocolVersion(Version)
-	client.ReadTeamSize()
-	game := client.ReadGameContext()
+	return r.RunClient(client)
+}
+
+// RunClient plays a game over an already connected client. It does not close
+// the client.
+func (r *Runner) RunClient(client *Client) error {
+	if err := client.WriteToken(r.token); err != nil {
+		return err
+	}
+	if err := client.WriteProtocolVersion(Version); err != nil {
+		return err
+	}
+	if _, err := client.ReadTeamSize(); err != nil {
+		return err
+	}
+	game, err := client.ReadGameContext()
+	if err != nil {
+		return err
+	}
 
 	var strategy Strategy
 	strategy = r.factory()
+	budget := newActionBudget(strategy, game)
 
-	playerContext := client.ReadPlayerContext()
+	playerContext, err := client.ReadPlayerContext()
 	for playerContext != nil {
 		player := playerContext.Player
 		if player == nil {
@@ -56,12 +160,47 @@ func (r *Runner) Run() error {
 
 		move := NewMove()
 
+		budget.Update(player, playerContext.World)
 		strategy.Move(player, playerContext.World, game, move)
+		if move, err = r.checkMove(move, player, playerContext.World, game); err != nil {
+			return err
+		}
+		budget.Record(move)
 
-		client.WriteMovesMessage(move)
+		if err := client.WriteMovesMessage(move); err != nil {
+			return err
+		}
+		if r.recorder != nil && playerContext.World != nil {
+			r.recorder.Tick(playerContext.World.TickIndex)
+		}
 
-		playerContext = client.ReadPlayerContext()
+		playerContext, err = client.ReadPlayerContext()
 	}
 
-	return nil
+	return err
+}
+
+func (r *Runner) checkMove(move *Move, me *Player, world *World, game *Game) (*Move, error) {
+	// Without a world there is nothing to check the move against.
+	if r.invalidMoves == InvalidMove_Send || world == nil {
+		return move, nil
+	}
+	err := move.Validate(me, world, game)
+	switch {
+	case err == nil:
+		return move, nil
+	case r.invalidMoves == InvalidMove_Fail:
+		return nil, fmt.Errorf("tick %d: %w", world.TickIndex, err)
+	case r.invalidMoves == InvalidMove_Log:
+		log.Printf("tick %d: %v", world.TickIndex, err)
+	}
+	return NewMove(), nil
+}
+
+func newActionBudget(strategy Strategy, game *Game) *ActionBudget {
+	budget := NewActionBudget(game)
+	if s, ok := strategy.(BudgetedStrategy); ok {
+		s.SetActionBudget(budget)
+	}
+	return budget
 }
@@ -0,0 +1,48 @@
+package runner
+
+import (
+	. "codewars"
+	"errors"
+	"io"
+	"net"
+	"testing"
+)
+
+type idleStrategy struct {
+	ticks int
+}
+
+func (s *idleStrategy) Move(me *Player, world *World, game *Game, move *Move) {
+	s.ticks++
+}
+
+func TestRunClientConnectionClosed(t *testing.T) {
+	strategyEnd, serverEnd := net.Pipe()
+	defer strategyEnd.Close()
+	go func() {
+		s := NewServerConn(serverEnd)
+		defer s.Close()
+		s.ReadToken()
+		s.ReadProtocolVersion()
+		s.WriteTeamSize(1)
+		g := NewGame()
+		s.WriteGameContext(g)
+		s.WritePlayerContext(testContexts(g)[0])
+		s.ReadMovesMessage()
+		// The server goes away in the middle of the game.
+	}()
+
+	strategy := &idleStrategy{}
+	r := New("", "token", func() Strategy { return strategy })
+	err := r.RunClient(NewClientConn(strategyEnd))
+	if err == nil {
+		t.Fatal("RunClient returned no error after the server closed the connection")
+	}
+	var pe *ProtocolError
+	if !errors.As(err, &pe) || !errors.Is(err, io.EOF) || pe.Expected != Message_PlayerContext {
+		t.Errorf("RunClient() = %v, want a ProtocolError with io.EOF on the next PlayerContext", err)
+	}
+	if strategy.ticks != 1 {
+		t.Errorf("strategy moved %d times, want 1", strategy.ticks)
+	}
+}
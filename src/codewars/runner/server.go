@@ -0,0 +1,407 @@
+package runner
+
+import (
+	. "codewars"
+	"io"
+	"net"
+)
+
+// ServerConn is the server side of the protocol spoken by Client: it writes
+// the messages a game server sends and decodes the Moves strategies answer
+// with. Like the real server it sends unchanged players with the 127 marker,
+// repeats unchanged player and facility lists as a negative length and sends
+// the terrain and weather maps with the first world only.
+type ServerConn struct {
+	c *Client
+
+	sentPlayerById map[int64]Player
+	sentPlayers    []Player
+	sentFacilities []Facility
+	sentMaps       bool
+}
+
+func NewServerConn(rw io.ReadWriter) *ServerConn {
+	return &ServerConn{c: NewClientConn(rw), sentPlayerById: make(map[int64]Player)}
+}
+
+// Accept waits for a strategy to connect to l.
+func Accept(l net.Listener) (*ServerConn, error) {
+	conn, err := l.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return NewServerConn(conn), nil
+}
+
+func (s *ServerConn) Close() error {
+	return s.c.Close()
+}
+
+func (s *ServerConn) Err() error {
+	return s.c.Err()
+}
+
+func (s *ServerConn) ReadToken() (string, error) {
+	return s.c.ReadToken()
+}
+
+func (s *ServerConn) ReadProtocolVersion() (int, error) {
+	return s.c.ReadProtocolVersion()
+}
+
+// ReadMovesMessage returns nil without an error when the strategy sent no move.
+func (s *ServerConn) ReadMovesMessage() (*Move, error) {
+	return s.c.ReadMovesMessage()
+}
+
+func (s *ServerConn) WriteTeamSize(size int) error {
+	c := s.c
+	c.writeOpcode(Message_TeamSize)
+	c.writeInt(size)
+	c.flush()
+	return c.wrap("TeamSize")
+}
+
+func (s *ServerConn) WriteGameOver() error {
+	c := s.c
+	c.writeOpcode(Message_GameOver)
+	c.flush()
+	return c.wrap("GameOver")
+}
+
+func (s *ServerConn) WriteGameContext(g *Game) error {
+	c := s.c
+	c.writeOpcode(Message_GameContext)
+	c.writeBool(g != nil)
+	if g != nil {
+		s.writeGame(g)
+	}
+	c.flush()
+	return c.wrap("Game")
+}
+
+// WritePlayerContext sends the state of the game as seen by ctx.Player. Only
+// NewVehicles and VehicleUpdate of the world are sent, not its VehicleById.
+func (s *ServerConn) WritePlayerContext(ctx *PlayerContext) error {
+	c := s.c
+	c.writeOpcode(Message_PlayerContext)
+	c.writeBool(ctx != nil)
+	if ctx != nil {
+		s.writePlayer(ctx.Player)
+		s.writeWorld(ctx.World)
+	}
+	c.flush()
+	return c.wrap("PlayerContext")
+}
+
+func (s *ServerConn) writeWorld(w *World) {
+	c := s.c
+	defer c.wrap("World")
+	c.writeBool(w != nil)
+	if w == nil {
+		return
+	}
+	c.writeInt(w.TickIndex)
+	c.writeInt(w.TickCount)
+	c.writeFloat64(w.Width)
+	c.writeFloat64(w.Height)
+	s.writePlayers(w.Players)
+	s.writeVehicles(w.NewVehicles)
+	s.writeVehicleUpdates(w.VehicleUpdate)
+	if !s.sentMaps {
+		s.writeTerrainByCellXY(w.TerrainByCellXY)
+		s.writeWeatherByCellXY(w.WeatherByCellXY)
+		s.sentMaps = true
+	}
+	s.writeFacilities(w.Facilities)
+}
+
+func (s *ServerConn) writePlayers(players []*Player) {
+	c := s.c
+	defer c.wrap("Players")
+	if s.sentPlayers != nil && s.samePlayers(players) {
+		c.writeInt(-1)
+		return
+	}
+	c.writeInt(len(players))
+	s.sentPlayers = make([]Player, len(players))
+	for i, p := range players {
+		s.writePlayer(p)
+		if p != nil {
+			s.sentPlayers[i] = *p
+		}
+	}
+}
+
+func (s *ServerConn) samePlayers(players []*Player) bool {
+	if len(players) != len(s.sentPlayers) {
+		return false
+	}
+	for i, p := range players {
+		if p == nil || *p != s.sentPlayers[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *ServerConn) writePlayer(p *Player) {
+	c := s.c
+	defer c.wrap("Player")
+	if p == nil {
+		c.writeByte(0)
+		return
+	}
+	if sent, ok := s.sentPlayerById[p.Id]; ok && sent == *p {
+		c.writeByte(127)
+		c.writeInt64(p.Id)
+		return
+	}
+	c.writeByte(1)
+	c.writeInt64(p.Id)
+	c.writeBool(p.Me)
+	c.writeBool(p.StrategyCrashed)
+	c.writeInt(p.Score)
+	c.writeInt(p.RemainingActionCooldownTicks)
+	c.writeInt(p.RemainingNuclearStrikeCooldownTicks)
+	c.writeInt64(p.NextNuclearStrikeVehicleId)
+	c.writeInt(p.NextNuclearStrikeTickIndex)
+	c.writeFloat64(p.NextNuclearStrikeX)
+	c.writeFloat64(p.NextNuclearStrikeY)
+	s.sentPlayerById[p.Id] = *p
+}
+
+func (s *ServerConn) writeVehicles(vehicles []*Vehicle) {
+	c := s.c
+	defer c.wrap("Vehicles")
+	c.writeInt(len(vehicles))
+	for _, v := range vehicles {
+		s.writeVehicle(v)
+	}
+}
+
+func (s *ServerConn) writeVehicle(v *Vehicle) {
+	c := s.c
+	defer c.wrap("Vehicle")
+	c.writeBool(v != nil)
+	if v == nil {
+		return
+	}
+	c.writeInt64(v.Id)
+	c.writeFloat64(v.X)
+	c.writeFloat64(v.Y)
+	c.writeFloat64(v.Radius)
+	c.writeInt64(v.PlayerId)
+	c.writeInt(v.Durability)
+	c.writeInt(v.MaxDurability)
+	c.writeFloat64(v.MaxSpeed)
+	c.writeFloat64(v.VisionRange)
+	c.writeFloat64(v.SquaredVisionRange)
+	c.writeFloat64(v.GroundAttackRange)
+	c.writeFloat64(v.SquaredGroundAttackRange)
+	c.writeFloat64(v.AerialAttackRange)
+	c.writeFloat64(v.SquaredAerialAttackRange)
+	c.writeInt(v.GroundDamage)
+	c.writeInt(v.AerialDamage)
+	c.writeInt(v.GroundDefence)
+	c.writeInt(v.AerialDefence)
+	c.writeInt(v.AttackCooldownTicks)
+	c.writeInt(v.RemainingAttackCooldownTicks)
+	c.writeVehicleType(v.VehicleType)
+	c.writeBool(v.Aerial)
+	c.writeBool(v.Selected)
+	c.writeIntArray(v.Groups)
+}
+
+func (s *ServerConn) writeVehicleUpdates(updates []*VehicleUpdate) {
+	c := s.c
+	defer c.wrap("VehicleUpdates")
+	c.writeInt(len(updates))
+	for _, u := range updates {
+		s.writeVehicleUpdate(u)
+	}
+}
+
+func (s *ServerConn) writeVehicleUpdate(u *VehicleUpdate) {
+	c := s.c
+	defer c.wrap("VehicleUpdate")
+	c.writeBool(u != nil)
+	if u == nil {
+		return
+	}
+	c.writeInt64(u.Id)
+	c.writeFloat64(u.X)
+	c.writeFloat64(u.Y)
+	c.writeInt(u.Durability)
+	c.writeInt(u.RemainingAttackCooldownTicks)
+	c.writeBool(u.Selected)
+	c.writeIntArray(u.Groups)
+}
+
+func (s *ServerConn) writeTerrainByCellXY(terrain [][]TerrainType) {
+	c := s.c
+	defer c.wrap("TerrainByCellXY")
+	c.writeInt(len(terrain))
+	for _, column := range terrain {
+		c.writeInt(len(column))
+		for _, t := range column {
+			c.writeTerrainType(t)
+		}
+	}
+}
+
+func (s *ServerConn) writeWeatherByCellXY(weather [][]WeatherType) {
+	c := s.c
+	defer c.wrap("WeatherByCellXY")
+	c.writeInt(len(weather))
+	for _, column := range weather {
+		c.writeInt(len(column))
+		for _, t := range column {
+			c.writeWeatherType(t)
+		}
+	}
+}
+
+func (s *ServerConn) writeFacilities(facilities []*Facility) {
+	c := s.c
+	defer c.wrap("Facilities")
+	if s.sentFacilities != nil && s.sameFacilities(facilities) {
+		c.writeInt(-1)
+		return
+	}
+	c.writeInt(len(facilities))
+	s.sentFacilities = make([]Facility, len(facilities))
+	for i, f := range facilities {
+		s.writeFacility(f)
+		if f != nil {
+			s.sentFacilities[i] = *f
+		}
+	}
+}
+
+func (s *ServerConn) sameFacilities(facilities []*Facility) bool {
+	if len(facilities) != len(s.sentFacilities) {
+		return false
+	}
+	for i, f := range facilities {
+		if f == nil || *f != s.sentFacilities[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *ServerConn) writeFacility(f *Facility) {
+	c := s.c
+	defer c.wrap("Facility")
+	c.writeBool(f != nil)
+	if f == nil {
+		return
+	}
+	c.writeInt64(f.Id)
+	c.writeFacilityType(f.Type)
+	c.writeInt64(f.OwnerPlayerId)
+	c.writeFloat64(f.Left)
+	c.writeFloat64(f.Top)
+	c.writeFloat64(f.CapturePoints)
+	c.writeVehicleType(f.VehicleType)
+	c.writeInt(f.ProductionProgress)
+}
+
+func (s *ServerConn) writeGame(g *Game) {
+	c := s.c
+	c.writeInt64(g.RandomSeed)
+	c.writeInt(g.TickCount)
+	c.writeFloat64(g.WorldWidth)
+	c.writeFloat64(g.WorldHeight)
+	c.writeBool(g.FogOfWarEnabled)
+	c.writeInt(g.VictoryScore)
+	c.writeInt(g.FacilityCaptureScore)
+	c.writeInt(g.VehicleEliminationScore)
+	c.writeInt(g.ActionDetectionInterval)
+	c.writeInt(g.BaseActionCount)
+	c.writeInt(g.AdditionalActionCountPerControlCenter)
+	c.writeInt(g.MaxUnitGroup)
+	c.writeInt(g.TerrainWeatherMapColumnCount)
+	c.writeInt(g.TerrainWeatherMapRowCount)
+	c.writeFloat64(g.PlainTerrainVisionFactor)
+	c.writeFloat64(g.PlainTerrainStealthFactor)
+	c.writeFloat64(g.PlainTerrainSpeedFactor)
+	c.writeFloat64(g.SwampTerrainVisionFactor)
+	c.writeFloat64(g.SwampTerrainStealthFactor)
+	c.writeFloat64(g.SwampTerrainSpeedFactor)
+	c.writeFloat64(g.ForestTerrainVisionFactor)
+	c.writeFloat64(g.ForestTerrainStealthFactor)
+	c.writeFloat64(g.ForestTerrainSpeedFactor)
+	c.writeFloat64(g.ClearWeatherVisionFactor)
+	c.writeFloat64(g.ClearWeatherStealthFactor)
+	c.writeFloat64(g.ClearWeatherSpeedFactor)
+	c.writeFloat64(g.CloudWeatherVisionFactor)
+	c.writeFloat64(g.CloudWeatherStealthFactor)
+	c.writeFloat64(g.CloudWeatherSpeedFactor)
+	c.writeFloat64(g.RainWeatherVisionFactor)
+	c.writeFloat64(g.RainWeatherStealthFactor)
+	c.writeFloat64(g.RainWeatherSpeedFactor)
+	c.writeFloat64(g.VehicleRadius)
+	c.writeInt(g.TankDurability)
+	c.writeFloat64(g.TankSpeed)
+	c.writeFloat64(g.TankVisionRange)
+	c.writeFloat64(g.TankGroundAttackRange)
+	c.writeFloat64(g.TankAerialAttackRange)
+	c.writeInt(g.TankGroundDamage)
+	c.writeInt(g.TankAerialDamage)
+	c.writeInt(g.TankGroundDefence)
+	c.writeInt(g.TankAerialDefence)
+	c.writeInt(g.TankAttackCooldownTicks)
+	c.writeInt(g.TankProductionCost)
+	c.writeInt(g.IfvDurability)
+	c.writeFloat64(g.IfvSpeed)
+	c.writeFloat64(g.IfvVisionRange)
+	c.writeFloat64(g.IfvGroundAttackRange)
+	c.writeFloat64(g.IfvAerialAttackRange)
+	c.writeInt(g.IfvGroundDamage)
+	c.writeInt(g.IfvAerialDamage)
+	c.writeInt(g.IfvGroundDefence)
+	c.writeInt(g.IfvAerialDefence)
+	c.writeInt(g.IfvAttackCooldownTicks)
+	c.writeInt(g.IfvProductionCost)
+	c.writeInt(g.ArrvDurability)
+	c.writeFloat64(g.ArrvSpeed)
+	c.writeFloat64(g.ArrvVisionRange)
+	c.writeInt(g.ArrvGroundDefence)
+	c.writeInt(g.ArrvAerialDefence)
+	c.writeInt(g.ArrvProductionCost)
+	c.writeFloat64(g.ArrvRepairRange)
+	c.writeFloat64(g.ArrvRepairSpeed)
+	c.writeInt(g.HelicopterDurability)
+	c.writeFloat64(g.HelicopterSpeed)
+	c.writeFloat64(g.HelicopterVisionRange)
+	c.writeFloat64(g.HelicopterGroundAttackRange)
+	c.writeFloat64(g.HelicopterAerialAttackRange)
+	c.writeInt(g.HelicopterGroundDamage)
+	c.writeInt(g.HelicopterAerialDamage)
+	c.writeInt(g.HelicopterGroundDefence)
+	c.writeInt(g.HelicopterAerialDefence)
+	c.writeInt(g.HelicopterAttackCooldownTicks)
+	c.writeInt(g.HelicopterProductionCost)
+	c.writeInt(g.FighterDurability)
+	c.writeFloat64(g.FighterSpeed)
+	c.writeFloat64(g.FighterVisionRange)
+	c.writeFloat64(g.FighterGroundAttackRange)
+	c.writeFloat64(g.FighterAerialAttackRange)
+	c.writeInt(g.FighterGroundDamage)
+	c.writeInt(g.FighterAerialDamage)
+	c.writeInt(g.FighterGroundDefence)
+	c.writeInt(g.FighterAerialDefence)
+	c.writeInt(g.FighterAttackCooldownTicks)
+	c.writeInt(g.FighterProductionCost)
+	c.writeFloat64(g.MaxFacilityCapturePoints)
+	c.writeFloat64(g.FacilityCapturePointsPerVehiclePerTick)
+	c.writeFloat64(g.FacilityWidth)
+	c.writeFloat64(g.FacilityHeight)
+	c.writeInt(g.BaseTacticalNuclearStrikeCooldown)
+	c.writeInt(g.TacticalNuclearStrikeCooldownDecreasePerControlCenter)
+	c.writeFloat64(g.MaxTacticalNuclearStrikeDamage)
+	c.writeFloat64(g.TacticalNuclearStrikeRadius)
+	c.writeInt(g.TacticalNuclearStrikeDelay)
+}
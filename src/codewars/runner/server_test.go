@@ -0,0 +1,375 @@
+package runner
+
+import (
+	"bytes"
+	. "codewars"
+	"encoding/binary"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// wire builds the bytes of a message by hand, in little-endian order as the
+// protocol says, independently of the encoder under test.
+type wire struct {
+	bytes.Buffer
+}
+
+func (w *wire) byte(v byte) { w.WriteByte(v) }
+
+func (w *wire) bool(v bool) {
+	if v {
+		w.byte(1)
+	} else {
+		w.byte(0)
+	}
+}
+
+func (w *wire) i32(v int) {
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], uint32(int32(v)))
+	w.Write(b[:])
+}
+
+func (w *wire) i64(v int64) {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], uint64(v))
+	w.Write(b[:])
+}
+
+func (w *wire) f64(v float64) {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], math.Float64bits(v))
+	w.Write(b[:])
+}
+
+// testGame returns a game with a different value in every field, so that a
+// field read from the wrong offset shows.
+func testGame() *Game {
+	g := &Game{}
+	v := reflect.ValueOf(g).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		switch f := v.Field(i); f.Kind() {
+		case reflect.Int, reflect.Int64:
+			f.SetInt(int64(1000 + i))
+		case reflect.Float64:
+			f.SetFloat(float64(i) + 0.25)
+		case reflect.Bool:
+			f.SetBool(true)
+		default:
+			panic("unexpected field " + v.Type().Field(i).Name)
+		}
+	}
+	return g
+}
+
+// gameFixture is the GameContext message carrying g, field by field in the
+// order and with the types of the official protocol.
+func gameFixture(g *Game) []byte {
+	var w wire
+	w.byte(byte(Message_GameContext))
+	w.bool(true)
+	w.i64(g.RandomSeed)
+	w.i32(g.TickCount)
+	w.f64(g.WorldWidth)
+	w.f64(g.WorldHeight)
+	w.bool(g.FogOfWarEnabled)
+	w.i32(g.VictoryScore)
+	w.i32(g.FacilityCaptureScore)
+	w.i32(g.VehicleEliminationScore)
+	w.i32(g.ActionDetectionInterval)
+	w.i32(g.BaseActionCount)
+	w.i32(g.AdditionalActionCountPerControlCenter)
+	w.i32(g.MaxUnitGroup)
+	w.i32(g.TerrainWeatherMapColumnCount)
+	w.i32(g.TerrainWeatherMapRowCount)
+	for _, f := range []float64{
+		g.PlainTerrainVisionFactor, g.PlainTerrainStealthFactor, g.PlainTerrainSpeedFactor,
+		g.SwampTerrainVisionFactor, g.SwampTerrainStealthFactor, g.SwampTerrainSpeedFactor,
+		g.ForestTerrainVisionFactor, g.ForestTerrainStealthFactor, g.ForestTerrainSpeedFactor,
+		g.ClearWeatherVisionFactor, g.ClearWeatherStealthFactor, g.ClearWeatherSpeedFactor,
+		g.CloudWeatherVisionFactor, g.CloudWeatherStealthFactor, g.CloudWeatherSpeedFactor,
+		g.RainWeatherVisionFactor, g.RainWeatherStealthFactor, g.RainWeatherSpeedFactor,
+		g.VehicleRadius,
+	} {
+		w.f64(f)
+	}
+	attacker := func(durability int, speed, vision, groundRange, aerialRange float64, groundDamage, aerialDamage, groundDefence, aerialDefence, cooldown, cost int) {
+		w.i32(durability)
+		w.f64(speed)
+		w.f64(vision)
+		w.f64(groundRange)
+		w.f64(aerialRange)
+		w.i32(groundDamage)
+		w.i32(aerialDamage)
+		w.i32(groundDefence)
+		w.i32(aerialDefence)
+		w.i32(cooldown)
+		w.i32(cost)
+	}
+	attacker(g.TankDurability, g.TankSpeed, g.TankVisionRange, g.TankGroundAttackRange, g.TankAerialAttackRange,
+		g.TankGroundDamage, g.TankAerialDamage, g.TankGroundDefence, g.TankAerialDefence, g.TankAttackCooldownTicks, g.TankProductionCost)
+	attacker(g.IfvDurability, g.IfvSpeed, g.IfvVisionRange, g.IfvGroundAttackRange, g.IfvAerialAttackRange,
+		g.IfvGroundDamage, g.IfvAerialDamage, g.IfvGroundDefence, g.IfvAerialDefence, g.IfvAttackCooldownTicks, g.IfvProductionCost)
+	w.i32(g.ArrvDurability)
+	w.f64(g.ArrvSpeed)
+	w.f64(g.ArrvVisionRange)
+	w.i32(g.ArrvGroundDefence)
+	w.i32(g.ArrvAerialDefence)
+	w.i32(g.ArrvProductionCost)
+	w.f64(g.ArrvRepairRange)
+	w.f64(g.ArrvRepairSpeed)
+	attacker(g.HelicopterDurability, g.HelicopterSpeed, g.HelicopterVisionRange, g.HelicopterGroundAttackRange, g.HelicopterAerialAttackRange,
+		g.HelicopterGroundDamage, g.HelicopterAerialDamage, g.HelicopterGroundDefence, g.HelicopterAerialDefence, g.HelicopterAttackCooldownTicks, g.HelicopterProductionCost)
+	attacker(g.FighterDurability, g.FighterSpeed, g.FighterVisionRange, g.FighterGroundAttackRange, g.FighterAerialAttackRange,
+		g.FighterGroundDamage, g.FighterAerialDamage, g.FighterGroundDefence, g.FighterAerialDefence, g.FighterAttackCooldownTicks, g.FighterProductionCost)
+	w.f64(g.MaxFacilityCapturePoints)
+	w.f64(g.FacilityCapturePointsPerVehiclePerTick)
+	w.f64(g.FacilityWidth)
+	w.f64(g.FacilityHeight)
+	w.i32(g.BaseTacticalNuclearStrikeCooldown)
+	w.i32(g.TacticalNuclearStrikeCooldownDecreasePerControlCenter)
+	w.f64(g.MaxTacticalNuclearStrikeDamage)
+	w.f64(g.TacticalNuclearStrikeRadius)
+	w.i32(g.TacticalNuclearStrikeDelay)
+	return w.Bytes()
+}
+
+func TestGameContextMatchesFixture(t *testing.T) {
+	g := testGame()
+	fixture := gameFixture(g)
+
+	var buf bytes.Buffer
+	if err := NewServerConn(&buf).WriteGameContext(g); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), fixture) {
+		t.Fatalf("encoded game differs from the fixture:\n got % x\nwant % x", buf.Bytes(), fixture)
+	}
+
+	decoded, err := NewClientConn(bytes.NewBuffer(fixture)).ReadGameContext()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *decoded != *g {
+		t.Errorf("decoded game = %+v, want %+v", *decoded, *g)
+	}
+}
+
+func TestNullGameContext(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewServerConn(&buf).WriteGameContext(nil); err != nil {
+		t.Fatal(err)
+	}
+	if want := []byte{byte(Message_GameContext), 0}; !bytes.Equal(buf.Bytes(), want) {
+		t.Fatalf("encoded null game = % x, want % x", buf.Bytes(), want)
+	}
+	if g, err := NewClientConn(&buf).ReadGameContext(); g != nil || err != nil {
+		t.Errorf("ReadGameContext() = %v, %v, want nil, nil", g, err)
+	}
+}
+
+// testContexts returns three successive contexts of a game: the first
+// world, one where neither the players nor the facilities changed and one
+// where the opponent scored.
+func testContexts(g *Game) []*PlayerContext {
+	me := &Player{Id: 1, Me: true, Score: 10, NextNuclearStrikeTickIndex: -1, NextNuclearStrikeVehicleId: -1}
+	opponent := &Player{Id: 2, Score: 20, NextNuclearStrikeTickIndex: -1, NextNuclearStrikeVehicleId: -1}
+	terrain := [][]TerrainType{{Terrain_Plain, Terrain_Swamp}, {Terrain_Forest, Terrain_Plain}}
+	weather := [][]WeatherType{{Weather_Clear, Weather_Cloud}, {Weather_Rain, Weather_Clear}}
+	facilities := []*Facility{
+		{Id: 7, Type: Facility_Control_Center, OwnerPlayerId: -1, Left: 64, Top: 128, VehicleType: Vehicle_Unknown,
+			Width: g.FacilityWidth, Height: g.FacilityHeight},
+		{Id: 8, Type: Facility_Vehicle_Factory, OwnerPlayerId: 1, Left: 192, Top: 0, CapturePoints: 100,
+			VehicleType: Vehicle_Tank, ProductionProgress: 12, Width: g.FacilityWidth, Height: g.FacilityHeight},
+	}
+	tank := &Vehicle{
+		CircularUnit: CircularUnit{Unit: Unit{Id: 100, X: 18, Y: 30}, Radius: 2},
+		PlayerId:     1, Durability: 100, MaxDurability: 100, MaxSpeed: 0.3,
+		VisionRange: 80, SquaredVisionRange: 6400, GroundAttackRange: 20, SquaredGroundAttackRange: 400,
+		AerialAttackRange: 18, SquaredAerialAttackRange: 324, GroundDamage: 100, AerialDamage: 60,
+		GroundDefence: 80, AerialDefence: 60, AttackCooldownTicks: 60,
+		VehicleType: Vehicle_Tank, Groups: []int{1, 3},
+	}
+	scored := *opponent
+	scored.Score = 21
+	return []*PlayerContext{
+		{me, &World{TickIndex: 0, TickCount: 20000, Width: 1024, Height: 1024,
+			Players: []*Player{me, opponent}, NewVehicles: []*Vehicle{tank},
+			TerrainByCellXY: terrain, WeatherByCellXY: weather, Facilities: facilities}},
+		{me, &World{TickIndex: 1, TickCount: 20000, Width: 1024, Height: 1024,
+			Players: []*Player{me, opponent}, Facilities: facilities}},
+		{me, &World{TickIndex: 2, TickCount: 20000, Width: 1024, Height: 1024,
+			Players: []*Player{me, &scored},
+			VehicleUpdate: []*VehicleUpdate{{Unit: Unit{Id: 100, X: 18.3, Y: 30}, Durability: 90,
+				RemainingAttackCooldownTicks: 5, Selected: true, Groups: []int{}}},
+			Facilities: facilities}},
+	}
+}
+
+func TestPlayerContextRoundTrip(t *testing.T) {
+	g := NewGame()
+	contexts := testContexts(g)
+
+	var buf bytes.Buffer
+	server := NewServerConn(&buf)
+	if err := server.WriteGameContext(g); err != nil {
+		t.Fatal(err)
+	}
+	var messages [][]byte
+	for _, ctx := range contexts {
+		start := buf.Len()
+		if err := server.WritePlayerContext(ctx); err != nil {
+			t.Fatal(err)
+		}
+		messages = append(messages, append([]byte(nil), buf.Bytes()[start:]...))
+	}
+	if err := server.WriteGameOver(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The second context repeats the first player, sent as the 127 marker
+	// and its id, repeats the player and facility lists, sent as a -1
+	// length, and has no maps.
+	var w wire
+	w.byte(byte(Message_PlayerContext))
+	w.bool(true)
+	w.byte(127)
+	w.i64(1)
+	w.bool(true)
+	w.i32(1)
+	w.i32(20000)
+	w.f64(1024)
+	w.f64(1024)
+	w.i32(-1)
+	w.i32(0)
+	w.i32(0)
+	w.i32(-1)
+	if !bytes.Equal(messages[1], w.Bytes()) {
+		t.Errorf("second context:\n got % x\nwant % x", messages[1], w.Bytes())
+	}
+	// The opponent scored: the list of two is sent again, the unchanged
+	// player still as a marker.
+	if n := int32(binary.LittleEndian.Uint32(messages[2][36:40])); n != 2 || messages[2][40] != 127 {
+		t.Errorf("third context players: length %d, first marker %d", n, messages[2][40])
+	}
+
+	client := NewClientConn(&buf)
+	if _, err := client.ReadGameContext(); err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range contexts {
+		got, err := client.ReadPlayerContext()
+		if err != nil {
+			t.Fatalf("context %d: %v", i, err)
+		}
+		if !reflect.DeepEqual(got.Player, want.Player) {
+			t.Errorf("context %d: player = %+v, want %+v", i, got.Player, want.Player)
+		}
+		gw, ww := got.World, want.World
+		if gw.TickIndex != ww.TickIndex || gw.TickCount != ww.TickCount || gw.Width != ww.Width || gw.Height != ww.Height {
+			t.Errorf("context %d: world header = %d %d %v %v", i, gw.TickIndex, gw.TickCount, gw.Width, gw.Height)
+		}
+		for _, c := range []struct {
+			name      string
+			got, want interface{}
+		}{
+			{"Players", gw.Players, ww.Players},
+			{"NewVehicles", gw.NewVehicles, ww.NewVehicles},
+			{"VehicleUpdate", gw.VehicleUpdate, ww.VehicleUpdate},
+			{"TerrainByCellXY", gw.TerrainByCellXY, contexts[0].World.TerrainByCellXY},
+			{"WeatherByCellXY", gw.WeatherByCellXY, contexts[0].World.WeatherByCellXY},
+			{"Facilities", gw.Facilities, ww.Facilities},
+		} {
+			if !reflect.DeepEqual(c.got, c.want) && !(isEmpty(c.got) && isEmpty(c.want)) {
+				t.Errorf("context %d: %s = %+v, want %+v", i, c.name, c.got, c.want)
+			}
+		}
+	}
+	if v := client.vehicleById[100]; v == nil || v.X != 18.3 || v.Durability != 90 || !v.Selected {
+		t.Errorf("vehicle after the update = %+v", v)
+	}
+
+	if ctx, err := client.ReadPlayerContext(); ctx != nil || err != nil {
+		t.Errorf("after GameOver ReadPlayerContext() = %v, %v, want nil, nil", ctx, err)
+	}
+}
+
+// isEmpty tells whether s is a nil or empty slice.
+func isEmpty(s interface{}) bool {
+	return reflect.ValueOf(s).Len() == 0
+}
+
+func TestGameOver(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewServerConn(&buf).WriteGameOver(); err != nil {
+		t.Fatal(err)
+	}
+	if want := []byte{byte(Message_GameOver)}; !bytes.Equal(buf.Bytes(), want) {
+		t.Fatalf("GameOver = % x, want % x", buf.Bytes(), want)
+	}
+	if ctx, err := NewClientConn(&buf).ReadPlayerContext(); ctx != nil || err != nil {
+		t.Errorf("ReadPlayerContext() = %v, %v, want nil, nil", ctx, err)
+	}
+}
+
+func TestTeamSizeRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewServerConn(&buf).WriteTeamSize(1); err != nil {
+		t.Fatal(err)
+	}
+	var w wire
+	w.byte(byte(Message_TeamSize))
+	w.i32(1)
+	if !bytes.Equal(buf.Bytes(), w.Bytes()) {
+		t.Fatalf("TeamSize = % x, want % x", buf.Bytes(), w.Bytes())
+	}
+	if n, err := NewClientConn(&buf).ReadTeamSize(); n != 1 || err != nil {
+		t.Errorf("ReadTeamSize() = %d, %v, want 1, nil", n, err)
+	}
+}
+
+func TestMovesRoundTrip(t *testing.T) {
+	moves := []*Move{
+		{Action: Action_Scale, Group: 2, X: 100, Y: 200, Factor: 0.5, Max_speed: 0.4, Vehicle_type: Vehicle_Unknown,
+			Facility_id: -1, Vehicle_id: -1},
+		{Action: Action_Tactical_Nuclear_Strike, X: 300, Y: 400, Vehicle_type: Vehicle_Helicopter, Vehicle_id: 42},
+		{Action: Action_Clear_And_Select, Left: 1, Top: 2, Right: 3, Bottom: 4, Vehicle_type: Vehicle_Fighter},
+		nil,
+	}
+	var buf bytes.Buffer
+	client := NewClientConn(&buf)
+	server := NewServerConn(&buf)
+	for i, want := range moves {
+		if err := client.WriteMovesMessage(want); err != nil {
+			t.Fatal(err)
+		}
+		got, err := server.ReadMovesMessage()
+		if err != nil {
+			t.Fatalf("move %d: %v", i, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("move %d = %+v, want %+v", i, got, want)
+		}
+	}
+
+	// The factor of a Scale goes between the angle and the speed.
+	var w wire
+	w.byte(byte(Message_Moves))
+	w.bool(true)
+	w.byte(byte(Action_Scale))
+	w.i32(0)
+	for _, f := range []float64{0, 0, 0, 0, 10, 20, 0, 1.5, 0, 0} {
+		w.f64(f)
+	}
+	w.byte(0xff)
+	w.i64(0)
+	w.i64(0)
+	got, err := NewServerConn(&w).ReadMovesMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Action != Action_Scale || got.X != 10 || got.Y != 20 || got.Factor != 1.5 || got.Vehicle_type != Vehicle_Unknown {
+		t.Errorf("decoded move = %+v", got)
+	}
+}
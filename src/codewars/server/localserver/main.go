@@ -0,0 +1,60 @@
+package main
+
+import (
+	. "codewars"
+	"codewars/runner"
+	"codewars/server"
+	"codewars/simulator"
+	"flag"
+	"log"
+	"os"
+)
+
+var (
+	addr     = flag.String("addr", server.DefaultAddr, "address to listen on")
+	token    = flag.String("token", "", "token the strategy must send, any if empty")
+	capture  = flag.String("capture", "", "serve the worlds recorded in this capture file")
+	ticks    = flag.Int("ticks", 0, "number of ticks to play, the game default if 0")
+	simulate = flag.Bool("simulate", false, "simulate a game from the standard start against an idle opponent")
+)
+
+type idle struct{}
+
+func (idle) Move(me *Player, world *World, game *Game, move *Move) {}
+
+func main() {
+	flag.Parse()
+
+	var source server.Source
+	if *capture != "" {
+		f, err := os.Open(*capture)
+		if err != nil {
+			log.Fatal(err)
+		}
+		c, err := runner.ReadCapture(f)
+		f.Close()
+		if err != nil {
+			log.Fatal(err)
+		}
+		if source, err = server.NewCaptureSource(c); err != nil {
+			log.Fatal(err)
+		}
+	} else {
+		game := NewGame()
+		if *ticks > 0 {
+			game.TickCount = *ticks
+		}
+		if *simulate {
+			source = simulator.NewSource(simulator.NewStandard(game), idle{})
+		} else {
+			source = server.NewEmptySource(game)
+		}
+	}
+
+	s := server.New(source)
+	s.Token = *token
+	if err := s.ListenAndServe(*addr); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("game over after %d moves", len(s.Moves))
+}
@@ -0,0 +1,100 @@
+package server
+
+import (
+	. "codewars"
+	"codewars/runner"
+	"errors"
+	"fmt"
+	"net"
+)
+
+const DefaultAddr = "127.0.0.1:31001"
+
+var ErrBadToken = errors.New("codewars: strategy sent a wrong token")
+
+// Source produces what the server tells the strategy on every tick.
+type Source interface {
+	Game() *Game
+	// Next returns the context of the next tick given the move the strategy
+	// made on the previous one, nil before the first tick. A nil context ends
+	// the game.
+	Next(move *Move) *runner.PlayerContext
+}
+
+// Server plays a game with a single strategy the way runner.Runner expects
+// the contest server to.
+type Server struct {
+	// Token is the token the strategy must authenticate with; empty accepts any.
+	Token    string
+	TeamSize int
+	Source   Source
+
+	// Moves collects the moves the strategy made, one per tick.
+	Moves []*Move
+}
+
+func New(source Source) *Server {
+	return &Server{TeamSize: 1, Source: source}
+}
+
+// ListenAndServe listens on addr, DefaultAddr if empty, and plays one game
+// with the first strategy that connects.
+func (s *Server) ListenAndServe(addr string) error {
+	if addr == "" {
+		addr = DefaultAddr
+	}
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer l.Close()
+	return s.Serve(l)
+}
+
+// Serve plays one game with the first strategy that connects to l.
+func (s *Server) Serve(l net.Listener) error {
+	conn, err := runner.Accept(l)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	return s.ServeConn(conn)
+}
+
+func (s *Server) ServeConn(conn *runner.ServerConn) error {
+	token, err := conn.ReadToken()
+	if err != nil {
+		return err
+	}
+	if s.Token != "" && token != s.Token {
+		return ErrBadToken
+	}
+	version, err := conn.ReadProtocolVersion()
+	if err != nil {
+		return err
+	}
+	if version != runner.Version {
+		return fmt.Errorf("codewars: unsupported protocol version %d", version)
+	}
+	if err := conn.WriteTeamSize(s.TeamSize); err != nil {
+		return err
+	}
+	if err := conn.WriteGameContext(s.Source.Game()); err != nil {
+		return err
+	}
+
+	var move *Move
+	for {
+		ctx := s.Source.Next(move)
+		if ctx == nil {
+			return conn.WriteGameOver()
+		}
+		if err := conn.WritePlayerContext(ctx); err != nil {
+			return err
+		}
+		if move, err = conn.ReadMovesMessage(); err != nil {
+			return err
+		}
+		s.Moves = append(s.Moves, move)
+	}
+}
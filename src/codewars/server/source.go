@@ -0,0 +1,106 @@
+package server
+
+import (
+	"bytes"
+	. "codewars"
+	"codewars/runner"
+	"errors"
+	"io"
+	"io/ioutil"
+)
+
+// EmptySource serves TickCount ticks of a world without vehicles or facilities.
+type EmptySource struct {
+	game    *Game
+	me      *Player
+	enemy   *Player
+	tick    int
+	terrain [][]TerrainType
+	weather [][]WeatherType
+}
+
+func NewEmptySource(game *Game) *EmptySource {
+	terrain := make([][]TerrainType, game.TerrainWeatherMapColumnCount)
+	weather := make([][]WeatherType, game.TerrainWeatherMapColumnCount)
+	for i := range terrain {
+		terrain[i] = make([]TerrainType, game.TerrainWeatherMapRowCount)
+		weather[i] = make([]WeatherType, game.TerrainWeatherMapRowCount)
+	}
+	return &EmptySource{
+		game:    game,
+		me:      &Player{Id: 1, Me: true, NextNuclearStrikeVehicleId: -1, NextNuclearStrikeTickIndex: -1},
+		enemy:   &Player{Id: 2, NextNuclearStrikeVehicleId: -1, NextNuclearStrikeTickIndex: -1},
+		terrain: terrain,
+		weather: weather,
+	}
+}
+
+func (s *EmptySource) Game() *Game {
+	return s.game
+}
+
+func (s *EmptySource) Next(move *Move) *runner.PlayerContext {
+	if s.tick >= s.game.TickCount {
+		return nil
+	}
+	w := &World{
+		TickIndex:       s.tick,
+		TickCount:       s.game.TickCount,
+		Width:           s.game.WorldWidth,
+		Height:          s.game.WorldHeight,
+		Players:         []*Player{s.me, s.enemy},
+		TerrainByCellXY: s.terrain,
+		WeatherByCellXY: s.weather,
+	}
+	s.tick++
+	return &runner.PlayerContext{Player: s.me, World: w}
+}
+
+// CaptureSource serves the worlds recorded in the server side of a capture,
+// whatever the strategy does.
+type CaptureSource struct {
+	game     *Game
+	contexts []*runner.PlayerContext
+}
+
+// NewCaptureSource decodes the worlds of capture. A capture ending between
+// two worlds is served up to there; any other decoding error is returned.
+func NewCaptureSource(capture *runner.Capture) (*CaptureSource, error) {
+	c := runner.NewClientReadWriter(bytes.NewReader(capture.Server), ioutil.Discard)
+	if _, err := c.ReadTeamSize(); err != nil {
+		return nil, err
+	}
+	game, err := c.ReadGameContext()
+	if err != nil {
+		return nil, err
+	}
+	s := &CaptureSource{game: game}
+	for {
+		ctx, err := c.ReadPlayerContext()
+		if errors.Is(err, io.EOF) {
+			// The capture stops before the end of the game.
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if ctx == nil {
+			break
+		}
+		s.contexts = append(s.contexts, ctx)
+	}
+	return s, nil
+}
+
+func (s *CaptureSource) Game() *Game {
+	return s.game
+}
+
+func (s *CaptureSource) Next(move *Move) *runner.PlayerContext {
+	if len(s.contexts) == 0 {
+		return nil
+	}
+	ctx := s.contexts[0]
+	s.contexts = s.contexts[1:]
+	return ctx
+}
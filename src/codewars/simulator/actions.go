@@ -0,0 +1,216 @@
+package simulator
+
+import (
+	. "codewars"
+	"math"
+)
+
+type order struct {
+	action ActionType
+	// target of a move, center of a rotation
+	x, y float64
+	// angle left to rotate by
+	angle           float64
+	maxSpeed        float64
+	maxAngularSpeed float64
+}
+
+// apply performs the move of a player if the action limit allows it.
+func (s *Simulator) apply(p *Player, move *Move) {
+	if move.Action == Action_None || p.RemainingActionCooldownTicks > 0 {
+		return
+	}
+	s.actionTicks[p.Id] = append(s.actionTicks[p.Id], s.TickIndex)
+
+	switch move.Action {
+	case Action_Clear_And_Select:
+		for _, v := range s.ownVehicles(p.Id) {
+			v.Selected = matches(v, move)
+		}
+	case Action_Add_To_Selection:
+		for _, v := range s.ownVehicles(p.Id) {
+			if matches(v, move) {
+				v.Selected = true
+			}
+		}
+	case Action_Deselect:
+		for _, v := range s.ownVehicles(p.Id) {
+			if matches(v, move) {
+				v.Selected = false
+			}
+		}
+	case Action_Assign:
+		if move.Group < 1 || move.Group > s.Game.MaxUnitGroup {
+			return
+		}
+		for _, v := range s.ownVehicles(p.Id) {
+			if v.Selected && !inGroup(v, move.Group) {
+				v.Groups = append(v.Groups, move.Group)
+			}
+		}
+	case Action_Dismiss:
+		for _, v := range s.ownVehicles(p.Id) {
+			if v.Selected {
+				v.Groups = withoutGroup(v.Groups, move.Group)
+			}
+		}
+	case Action_Disband:
+		for _, v := range s.ownVehicles(p.Id) {
+			v.Groups = withoutGroup(v.Groups, move.Group)
+		}
+	case Action_Move:
+		for _, v := range s.selected(p.Id) {
+			s.orders[v.Id] = &order{action: Action_Move, x: v.X + move.X, y: v.Y + move.Y, maxSpeed: move.Max_speed}
+		}
+	case Action_Rotate:
+		for _, v := range s.selected(p.Id) {
+			s.orders[v.Id] = &order{
+				action:          Action_Rotate,
+				x:               move.X,
+				y:               move.Y,
+				angle:           move.Angle,
+				maxSpeed:        move.Max_speed,
+				maxAngularSpeed: move.Max_angular_speed,
+			}
+		}
+	case Action_Scale:
+		for _, v := range s.selected(p.Id) {
+			x := move.X + (v.X-move.X)*move.Factor
+			y := move.Y + (v.Y-move.Y)*move.Factor
+			s.orders[v.Id] = &order{action: Action_Move, x: x, y: y, maxSpeed: move.Max_speed}
+		}
+	case Action_Setup_Vehicle_Production:
+		for _, f := range s.Facilities {
+			if f.Id == move.Facility_id && f.Type == Facility_Vehicle_Factory && f.OwnerPlayerId == p.Id {
+				f.VehicleType = move.Vehicle_type
+				f.ProductionProgress = 0
+			}
+		}
+	case Action_Tactical_Nuclear_Strike:
+		s.launch(p, move)
+	}
+}
+
+func (s *Simulator) selected(playerId int64) []*Vehicle {
+	var r []*Vehicle
+	for _, v := range s.ownVehicles(playerId) {
+		if v.Selected {
+			r = append(r, v)
+		}
+	}
+	return r
+}
+
+// matches tells whether a selection move picks v: by group if one is set,
+// otherwise by rectangle, and by type unless it is Vehicle_Unknown.
+func matches(v *Vehicle, move *Move) bool {
+	if move.Vehicle_type != Vehicle_Unknown && v.VehicleType != move.Vehicle_type {
+		return false
+	}
+	if move.Group > 0 {
+		return inGroup(v, move.Group)
+	}
+	return v.X >= move.Left && v.X <= move.Right && v.Y >= move.Top && v.Y <= move.Bottom
+}
+
+func inGroup(v *Vehicle, group int) bool {
+	for _, g := range v.Groups {
+		if g == group {
+			return true
+		}
+	}
+	return false
+}
+
+func withoutGroup(groups []int, group int) []int {
+	r := groups[:0]
+	for _, g := range groups {
+		if g != group {
+			r = append(r, g)
+		}
+	}
+	return r
+}
+
+func (s *Simulator) moveVehicles() {
+	for _, v := range s.Vehicles() {
+		o := s.orders[v.Id]
+		if o == nil {
+			continue
+		}
+		speed := v.MaxSpeed * s.world.SpeedFactorAt(s.Game, v.X, v.Y, v.Aerial)
+		if o.maxSpeed > 0 && o.maxSpeed < speed {
+			speed = o.maxSpeed
+		}
+		var done bool
+		if o.action == Action_Rotate {
+			done = s.rotate(v, o, speed)
+		} else {
+			done = s.moveTo(v, o.x, o.y, speed)
+		}
+		if done {
+			delete(s.orders, v.Id)
+		}
+	}
+}
+
+func (s *Simulator) moveTo(v *Vehicle, x, y, speed float64) bool {
+	d := v.GetDistanceTo(x, y)
+	if d <= speed {
+		s.place(v, x, y)
+		return true
+	}
+	s.place(v, v.X+(x-v.X)/d*speed, v.Y+(y-v.Y)/d*speed)
+	return false
+}
+
+func (s *Simulator) rotate(v *Vehicle, o *order, speed float64) bool {
+	r := v.GetDistanceTo(o.x, o.y)
+	step := math.Abs(o.angle)
+	if o.maxAngularSpeed > 0 && o.maxAngularSpeed < step {
+		step = o.maxAngularSpeed
+	}
+	if r > 0 && speed/r < step {
+		step = speed / r
+	}
+	if o.angle < 0 {
+		step = -step
+	}
+	a := math.Atan2(v.Y-o.y, v.X-o.x) + step
+	s.place(v, o.x+r*math.Cos(a), o.y+r*math.Sin(a))
+	o.angle -= step
+	return math.Abs(o.angle) < 1e-9
+}
+
+// place moves v to (x, y) keeping it inside the world.
+func (s *Simulator) place(v *Vehicle, x, y float64) {
+	v.X = math.Max(v.Radius, math.Min(s.Game.WorldWidth-v.Radius, x))
+	v.Y = math.Max(v.Radius, math.Min(s.Game.WorldHeight-v.Radius, y))
+}
+
+// cooldown counts down every cooldown and works out from the actions made in
+// the last ActionDetectionInterval ticks when each player may act again.
+func (s *Simulator) cooldown() {
+	for _, v := range s.vehicles {
+		if v.RemainingAttackCooldownTicks > 0 {
+			v.RemainingAttackCooldownTicks--
+		}
+	}
+	next := s.TickIndex + 1
+	for _, p := range s.Players {
+		ticks := s.actionTicks[p.Id]
+		for len(ticks) > 0 && ticks[0] <= next-s.Game.ActionDetectionInterval {
+			ticks = ticks[1:]
+		}
+		s.actionTicks[p.Id] = ticks
+
+		limit := s.Game.BaseActionCount + s.Game.AdditionalActionCountPerControlCenter*s.controlCenterCount(p.Id)
+		p.RemainingActionCooldownTicks = 0
+		if limit > 0 && len(ticks) >= limit {
+			p.RemainingActionCooldownTicks = ticks[len(ticks)-limit] + s.Game.ActionDetectionInterval - next
+		}
+		if p.RemainingNuclearStrikeCooldownTicks > 0 {
+			p.RemainingNuclearStrikeCooldownTicks--
+		}
+	}
+}
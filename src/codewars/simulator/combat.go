@@ -0,0 +1,224 @@
+package simulator
+
+import (
+	. "codewars"
+	"math"
+)
+
+// attack lets every vehicle that is ready fire at the enemy in range it hurts
+// most, the nearest one on a tie. All shots of a tick land together.
+func (s *Simulator) attack() {
+	vehicles := s.Vehicles()
+	maxRange := 0.0
+	for _, v := range vehicles {
+		maxRange = math.Max(maxRange, math.Max(v.GroundAttackRange, v.AerialAttackRange))
+	}
+	grid := s.newGrid(vehicles, maxRange)
+	hits := make(map[int64]int)
+	shooter := make(map[int64]int64)
+	for _, v := range vehicles {
+		if v.RemainingAttackCooldownTicks > 0 || v.Durability <= 0 {
+			continue
+		}
+		var target *Vehicle
+		best, bestDistance := 0, 0.0
+		grid.near(v.X, v.Y, func(u *Vehicle) bool {
+			if u.PlayerId == v.PlayerId || u.Durability <= 0 {
+				return true
+			}
+			d := damage(v, u)
+			if d <= 0 {
+				return true
+			}
+			distance := v.GetDistanceToUnit(&u.Unit)
+			if distance > attackRange(v, u) {
+				return true
+			}
+			if d > best || d == best && distance < bestDistance {
+				target, best, bestDistance = u, d, distance
+			}
+			return true
+		})
+		if target != nil {
+			hits[target.Id] += best
+			shooter[target.Id] = v.PlayerId
+			v.RemainingAttackCooldownTicks = v.AttackCooldownTicks
+		}
+	}
+	for id, d := range hits {
+		v := s.vehicles[id]
+		v.Durability -= d
+		if v.Durability <= 0 {
+			if p := s.Player(shooter[id]); p != nil {
+				p.Score += s.Game.VehicleEliminationScore
+			}
+		}
+	}
+}
+
+// damage returns what one shot of attacker takes from target's durability:
+// the damage against the kind of the target less the defence against the
+// kind of the attacker.
+func damage(attacker, target *Vehicle) int {
+	d := attacker.GroundDamage
+	if target.Aerial {
+		d = attacker.AerialDamage
+	}
+	if attacker.Aerial {
+		return d - target.AerialDefence
+	}
+	return d - target.GroundDefence
+}
+
+func attackRange(attacker, target *Vehicle) float64 {
+	if target.Aerial {
+		return attacker.AerialAttackRange
+	}
+	return attacker.GroundAttackRange
+}
+
+// repairVehicles restores ArrvRepairSpeed durability per tick to every damaged
+// vehicle with a friendly ARRV other than itself in repair range.
+func (s *Simulator) repairVehicles() {
+	vehicles := s.Vehicles()
+	var arrvs []*Vehicle
+	for _, v := range vehicles {
+		if v.VehicleType == Vehicle_Arrv {
+			arrvs = append(arrvs, v)
+		}
+	}
+	grid := s.newGrid(arrvs, s.Game.ArrvRepairRange)
+	for _, v := range vehicles {
+		if v.Durability <= 0 || v.Durability >= v.MaxDurability {
+			delete(s.repair, v.Id)
+			continue
+		}
+		grid.near(v.X, v.Y, func(a *Vehicle) bool {
+			if a.Id == v.Id || a.PlayerId != v.PlayerId || a.GetDistanceToUnit(&v.Unit) > s.Game.ArrvRepairRange {
+				return true
+			}
+			s.repair[v.Id] += s.Game.ArrvRepairSpeed
+			whole := math.Floor(s.repair[v.Id])
+			v.Durability += int(whole)
+			s.repair[v.Id] -= whole
+			if v.Durability > v.MaxDurability {
+				v.Durability = v.MaxDurability
+			}
+			return false
+		})
+	}
+}
+
+func (s *Simulator) removeDestroyed() {
+	for id, v := range s.vehicles {
+		if v.Durability <= 0 {
+			delete(s.vehicles, id)
+			delete(s.orders, id)
+			delete(s.repair, id)
+			s.sorted = nil
+		}
+	}
+	for _, p := range s.Players {
+		if p.NextNuclearStrikeTickIndex >= 0 && s.vehicles[p.NextNuclearStrikeVehicleId] == nil {
+			cancelStrike(p)
+		}
+	}
+}
+
+// launch orders a tactical nuclear strike at (move.X, move.Y) guided by the
+// player's vehicle move.Vehicle_id, which must see the target.
+func (s *Simulator) launch(p *Player, move *Move) {
+	v := s.vehicles[move.Vehicle_id]
+	if p.RemainingNuclearStrikeCooldownTicks > 0 || v == nil || v.PlayerId != p.Id {
+		return
+	}
+	vision := v.VisionRange * s.world.VisionFactorAt(s.Game, v.X, v.Y, v.Aerial)
+	if v.GetDistanceTo(move.X, move.Y) > vision {
+		return
+	}
+	p.NextNuclearStrikeVehicleId = v.Id
+	p.NextNuclearStrikeTickIndex = s.TickIndex + s.Game.TacticalNuclearStrikeDelay
+	p.NextNuclearStrikeX = move.X
+	p.NextNuclearStrikeY = move.Y
+	cooldown := s.Game.BaseTacticalNuclearStrikeCooldown -
+		s.Game.TacticalNuclearStrikeCooldownDecreasePerControlCenter*s.controlCenterCount(p.Id)
+	if cooldown < 0 {
+		cooldown = 0
+	}
+	p.RemainingNuclearStrikeCooldownTicks = cooldown
+}
+
+// detonate lands the strikes due this tick. The damage falls linearly from
+// MaxTacticalNuclearStrikeDamage at the center to zero at the strike radius
+// and hits vehicles of both players.
+func (s *Simulator) detonate() {
+	for _, p := range s.Players {
+		if p.NextNuclearStrikeTickIndex != s.TickIndex {
+			continue
+		}
+		for _, v := range s.vehicles {
+			d := v.GetDistanceTo(p.NextNuclearStrikeX, p.NextNuclearStrikeY)
+			if d < s.Game.TacticalNuclearStrikeRadius {
+				v.Durability -= int(s.Game.MaxTacticalNuclearStrikeDamage * (1 - d/s.Game.TacticalNuclearStrikeRadius))
+			}
+		}
+		cancelStrike(p)
+	}
+}
+
+func cancelStrike(p *Player) {
+	p.NextNuclearStrikeVehicleId = -1
+	p.NextNuclearStrikeTickIndex = -1
+	p.NextNuclearStrikeX = 0
+	p.NextNuclearStrikeY = 0
+}
+
+// grid buckets vehicles into square cells so that everything within the cell
+// size of a point is found in the 3 by 3 cells around it.
+type grid struct {
+	size          float64
+	columns, rows int
+	cells         [][]*Vehicle
+}
+
+func (s *Simulator) newGrid(vehicles []*Vehicle, size float64) *grid {
+	size = math.Max(size, 1)
+	g := &grid{
+		size:    size,
+		columns: int(s.Game.WorldWidth/size) + 1,
+		rows:    int(s.Game.WorldHeight/size) + 1,
+	}
+	g.cells = make([][]*Vehicle, g.columns*g.rows)
+	for _, v := range vehicles {
+		i, j := g.cell(v.X, v.Y)
+		g.cells[i*g.rows+j] = append(g.cells[i*g.rows+j], v)
+	}
+	return g
+}
+
+func (g *grid) cell(x, y float64) (int, int) {
+	i := int(math.Max(0, math.Min(float64(g.columns-1), x/g.size)))
+	j := int(math.Max(0, math.Min(float64(g.rows-1), y/g.size)))
+	return i, j
+}
+
+// near calls f for the vehicles of the cells around (x, y), in id order
+// within a cell, until f returns false.
+func (g *grid) near(x, y float64, f func(v *Vehicle) bool) {
+	ci, cj := g.cell(x, y)
+	for i := ci - 1; i <= ci+1; i++ {
+		if i < 0 || i >= g.columns {
+			continue
+		}
+		for j := cj - 1; j <= cj+1; j++ {
+			if j < 0 || j >= g.rows {
+				continue
+			}
+			for _, v := range g.cells[i*g.rows+j] {
+				if !f(v) {
+					return
+				}
+			}
+		}
+	}
+}
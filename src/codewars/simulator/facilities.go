@@ -0,0 +1,107 @@
+package simulator
+
+import (
+	. "codewars"
+	"math"
+)
+
+// captureFacilities moves the capture points of every facility by
+// FacilityCapturePointsPerVehiclePerTick for each ground vehicle the first
+// player has on it more than the second. Capture points range from
+// -MaxFacilityCapturePoints, owned by the second player, to
+// MaxFacilityCapturePoints, owned by the first; an owner loses the facility
+// once the points cross zero.
+func (s *Simulator) captureFacilities() {
+	first, second := s.Players[0], s.Players[1]
+	max := s.Game.MaxFacilityCapturePoints
+	for _, f := range s.Facilities {
+		balance := 0
+		for _, v := range s.vehicles {
+			if v.Aerial || !f.Contains(v.X, v.Y) {
+				continue
+			}
+			if v.PlayerId == first.Id {
+				balance++
+			} else {
+				balance--
+			}
+		}
+		f.CapturePoints += float64(balance) * s.Game.FacilityCapturePointsPerVehiclePerTick
+		f.CapturePoints = math.Max(-max, math.Min(max, f.CapturePoints))
+
+		owner := f.OwnerPlayerId
+		switch {
+		case f.CapturePoints >= max:
+			owner = first.Id
+		case f.CapturePoints <= -max:
+			owner = second.Id
+		case owner == first.Id && f.CapturePoints < 0, owner == second.Id && f.CapturePoints > 0:
+			owner = -1
+		}
+		if owner != f.OwnerPlayerId {
+			f.OwnerPlayerId = owner
+			f.VehicleType = Vehicle_Unknown
+			f.ProductionProgress = 0
+			if p := s.Player(owner); p != nil {
+				p.Score += s.Game.FacilityCaptureScore
+			}
+		}
+	}
+}
+
+// produce advances the owned factories and rolls out a vehicle once its
+// production cost is paid, as soon as there is room for it on the factory.
+func (s *Simulator) produce() {
+	for _, f := range s.Facilities {
+		if f.Type != Facility_Vehicle_Factory || f.OwnerPlayerId < 0 || f.VehicleType == Vehicle_Unknown {
+			continue
+		}
+		cost := productionCost(s.Game, f.VehicleType)
+		if f.ProductionProgress < cost {
+			f.ProductionProgress++
+		}
+		if f.ProductionProgress < cost {
+			continue
+		}
+		if x, y, ok := s.freeSpot(f, f.VehicleType == Vehicle_Fighter || f.VehicleType == Vehicle_Helicopter); ok {
+			s.AddVehicle(f.OwnerPlayerId, f.VehicleType, x, y)
+			f.ProductionProgress = 0
+		}
+	}
+}
+
+func (s *Simulator) freeSpot(f *Facility, aerial bool) (float64, float64, bool) {
+	r := s.Game.VehicleRadius
+	step := 2*r + 2
+	for y := f.Top + r; y <= f.Top+s.Game.FacilityHeight-r; y += step {
+		for x := f.Left + r; x <= f.Left+s.Game.FacilityWidth-r; x += step {
+			free := true
+			for _, v := range s.vehicles {
+				if v.Aerial == aerial && v.GetDistanceTo(x, y) < v.Radius+r {
+					free = false
+					break
+				}
+			}
+			if free {
+				return x, y, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+func productionCost(game *Game, t VehicleType) int {
+	switch t {
+	case Vehicle_Tank:
+		return game.TankProductionCost
+	case Vehicle_Ifv:
+		return game.IfvProductionCost
+	case Vehicle_Arrv:
+		return game.ArrvProductionCost
+	case Vehicle_Helicopter:
+		return game.HelicopterProductionCost
+	case Vehicle_Fighter:
+		return game.FighterProductionCost
+	}
+	return 0
+}
@@ -0,0 +1,50 @@
+package simulator
+
+import (
+	. "codewars"
+	"codewars/runner"
+)
+
+// Play runs the game to the end between two in-process strategies, first
+// playing as Players[0] and second as Players[1].
+func (s *Simulator) Play(first, second Strategy) {
+	for !s.Over() {
+		s.Tick([]*Move{s.ask(first, 0), s.ask(second, 1)})
+	}
+}
+
+func (s *Simulator) ask(strategy Strategy, player int) *Move {
+	w := s.World(s.Players[player].Id)
+	move := NewMove()
+	strategy.Move(w.GetMyPlayer(), w, s.Game, move)
+	return move
+}
+
+// Source serves the simulated game to a strategy playing as the first player
+// over the protocol, e.g. through server.Server, against an in-process
+// opponent playing as the second one.
+type Source struct {
+	sim      *Simulator
+	opponent Strategy
+	started  bool
+}
+
+func NewSource(sim *Simulator, opponent Strategy) *Source {
+	return &Source{sim: sim, opponent: opponent}
+}
+
+func (s *Source) Game() *Game {
+	return s.sim.Game
+}
+
+func (s *Source) Next(move *Move) *runner.PlayerContext {
+	if s.started {
+		s.sim.Tick([]*Move{move, s.sim.ask(s.opponent, 1)})
+	}
+	s.started = true
+	if s.sim.Over() {
+		return nil
+	}
+	w := s.sim.World(s.sim.Players[0].Id)
+	return &runner.PlayerContext{Player: w.GetMyPlayer(), World: w}
+}
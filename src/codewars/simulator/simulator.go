@@ -0,0 +1,346 @@
+package simulator
+
+import (
+	. "codewars"
+	"sort"
+)
+
+// Simulator advances a two-player game tick by tick following the rules in
+// Game. It models movement with terrain and weather speed factors, attacks,
+// ARRV repair, facility capture and production, action limits and tactical
+// nuclear strikes. Vehicles do not collide and there is no fog of war.
+type Simulator struct {
+	Game      *Game
+	TickIndex int
+	// Players holds the first and the second player, with ids 1 and 2.
+	Players    []*Player
+	Facilities []*Facility
+
+	world    *World
+	vehicles map[int64]*Vehicle
+	sorted   []*Vehicle
+	nextId   int64
+
+	orders      map[int64]*order
+	repair      map[int64]float64
+	actionTicks map[int64][]int
+	sent        map[int64]map[int64]Vehicle
+}
+
+// New returns a simulator of an empty world with plain terrain and clear
+// weather everywhere.
+func New(game *Game) *Simulator {
+	terrain := make([][]TerrainType, game.TerrainWeatherMapColumnCount)
+	weather := make([][]WeatherType, game.TerrainWeatherMapColumnCount)
+	for i := range terrain {
+		terrain[i] = make([]TerrainType, game.TerrainWeatherMapRowCount)
+		weather[i] = make([]WeatherType, game.TerrainWeatherMapRowCount)
+	}
+	s := &Simulator{
+		Game: game,
+		world: &World{
+			TickCount:       game.TickCount,
+			Width:           game.WorldWidth,
+			Height:          game.WorldHeight,
+			TerrainByCellXY: terrain,
+			WeatherByCellXY: weather,
+		},
+		vehicles:    make(map[int64]*Vehicle),
+		nextId:      1,
+		orders:      make(map[int64]*order),
+		repair:      make(map[int64]float64),
+		actionTicks: make(map[int64][]int),
+		sent:        make(map[int64]map[int64]Vehicle),
+	}
+	for id := int64(1); id <= 2; id++ {
+		s.Players = append(s.Players, &Player{
+			Id:                         id,
+			NextNuclearStrikeVehicleId: -1,
+			NextNuclearStrikeTickIndex: -1,
+		})
+	}
+	return s
+}
+
+// NewStandard returns a simulator with the usual starting position: a 10 by
+// 10 formation of every vehicle type per player, the second player's
+// mirrored into the opposite corner.
+func NewStandard(game *Game) *Simulator {
+	s := New(game)
+	types := []VehicleType{Vehicle_Tank, Vehicle_Ifv, Vehicle_Arrv, Vehicle_Helicopter, Vehicle_Fighter}
+	for slot, t := range types {
+		left := 18 + 74*float64(slot%3)
+		top := 18 + 74*float64(slot/3)
+		for i := 0; i < 10; i++ {
+			for j := 0; j < 10; j++ {
+				x, y := left+6*float64(i), top+6*float64(j)
+				s.AddVehicle(1, t, x, y)
+				s.AddVehicle(2, t, game.WorldWidth-x, game.WorldHeight-y)
+			}
+		}
+	}
+	return s
+}
+
+// SetTerrain and SetWeather replace the maps, indexed by column then row.
+func (s *Simulator) SetTerrain(terrain [][]TerrainType) {
+	s.world.TerrainByCellXY = terrain
+}
+
+func (s *Simulator) SetWeather(weather [][]WeatherType) {
+	s.world.WeatherByCellXY = weather
+}
+
+func (s *Simulator) AddVehicle(playerId int64, t VehicleType, x, y float64) *Vehicle {
+	v := newVehicle(s.Game, t)
+	v.Id = s.nextId
+	v.PlayerId = playerId
+	v.X, v.Y = x, y
+	s.nextId++
+	s.vehicles[v.Id] = v
+	s.sorted = nil
+	return v
+}
+
+// AddFacility adds a neutral facility with its top left corner at (left, top).
+func (s *Simulator) AddFacility(t FacilityType, left, top float64) *Facility {
+	f := &Facility{
+		Id:            int64(len(s.Facilities) + 1),
+		Type:          t,
+		OwnerPlayerId: -1,
+		Left:          left,
+		Top:           top,
+		VehicleType:   Vehicle_Unknown,
+		Width:         s.Game.FacilityWidth,
+		Height:        s.Game.FacilityHeight,
+	}
+	s.Facilities = append(s.Facilities, f)
+	return f
+}
+
+func (s *Simulator) Player(id int64) *Player {
+	for _, p := range s.Players {
+		if p.Id == id {
+			return p
+		}
+	}
+	return nil
+}
+
+// Vehicles returns the live vehicles ordered by id. They belong to the
+// simulator and must not be modified.
+func (s *Simulator) Vehicles() []*Vehicle {
+	if s.sorted == nil {
+		s.sorted = make([]*Vehicle, 0, len(s.vehicles))
+		for _, v := range s.vehicles {
+			s.sorted = append(s.sorted, v)
+		}
+		sort.Slice(s.sorted, func(i, j int) bool { return s.sorted[i].Id < s.sorted[j].Id })
+	}
+	return s.sorted
+}
+
+// Over reports whether the game has ended: the last tick has passed or a
+// player has no vehicles left.
+func (s *Simulator) Over() bool {
+	if s.TickIndex >= s.Game.TickCount {
+		return true
+	}
+	alive := make(map[int64]bool)
+	for _, v := range s.vehicles {
+		alive[v.PlayerId] = true
+	}
+	for _, p := range s.Players {
+		if !alive[p.Id] {
+			return true
+		}
+	}
+	return false
+}
+
+// Tick applies the moves of the players, in the order of Players, and
+// advances the game by one tick. A nil move does nothing.
+func (s *Simulator) Tick(moves []*Move) {
+	for i, move := range moves {
+		if i < len(s.Players) && move != nil {
+			s.apply(s.Players[i], move)
+		}
+	}
+	s.detonate()
+	s.moveVehicles()
+	s.attack()
+	s.repairVehicles()
+	s.removeDestroyed()
+	s.captureFacilities()
+	s.produce()
+	s.cooldown()
+	s.TickIndex++
+}
+
+// World returns the state of the game as the given player sees it. Like the
+// server it lists in NewVehicles the vehicles the player has not been told
+// about yet and in VehicleUpdate the changes since the previous call, with
+// zero durability for vehicles that are gone. VehicleById holds copies of all
+// vehicles. Facility capture points are positive towards the player.
+func (s *Simulator) World(playerId int64) *World {
+	w := *s.world
+	w.TickIndex = s.TickIndex
+	w.VehicleById = make(map[int64]*Vehicle, len(s.vehicles))
+
+	for _, p := range s.Players {
+		c := *p
+		c.Me = p.Id == playerId
+		w.Players = append(w.Players, &c)
+	}
+	for _, f := range s.Facilities {
+		c := *f
+		if playerId != s.Players[0].Id {
+			c.CapturePoints = -c.CapturePoints
+		}
+		w.Facilities = append(w.Facilities, &c)
+	}
+
+	sent := s.sent[playerId]
+	if sent == nil {
+		sent = make(map[int64]Vehicle)
+		s.sent[playerId] = sent
+	}
+	for _, v := range s.Vehicles() {
+		c := copyVehicle(v)
+		w.VehicleById[v.Id] = c
+		prev, ok := sent[v.Id]
+		switch {
+		case !ok:
+			w.NewVehicles = append(w.NewVehicles, copyVehicle(v))
+		case changed(&prev, v):
+			w.VehicleUpdate = append(w.VehicleUpdate, updateOf(v))
+		}
+		sent[v.Id] = *c
+	}
+	gone := make([]int64, 0)
+	for id := range sent {
+		if _, ok := s.vehicles[id]; !ok {
+			gone = append(gone, id)
+		}
+	}
+	sort.Slice(gone, func(i, j int) bool { return gone[i] < gone[j] })
+	for _, id := range gone {
+		w.VehicleUpdate = append(w.VehicleUpdate, &VehicleUpdate{Unit: Unit{Id: id}})
+		delete(sent, id)
+	}
+	return &w
+}
+
+func copyVehicle(v *Vehicle) *Vehicle {
+	c := *v
+	c.Groups = append([]int(nil), v.Groups...)
+	return &c
+}
+
+func changed(prev, v *Vehicle) bool {
+	if prev.X != v.X || prev.Y != v.Y || prev.Durability != v.Durability ||
+		prev.RemainingAttackCooldownTicks != v.RemainingAttackCooldownTicks ||
+		prev.Selected != v.Selected || len(prev.Groups) != len(v.Groups) {
+		return true
+	}
+	for i := range prev.Groups {
+		if prev.Groups[i] != v.Groups[i] {
+			return true
+		}
+	}
+	return false
+}
+
+func updateOf(v *Vehicle) *VehicleUpdate {
+	return &VehicleUpdate{
+		Unit:                         v.Unit,
+		Durability:                   v.Durability,
+		RemainingAttackCooldownTicks: v.RemainingAttackCooldownTicks,
+		Selected:                     v.Selected,
+		Groups:                       append([]int(nil), v.Groups...),
+	}
+}
+
+func newVehicle(game *Game, t VehicleType) *Vehicle {
+	v := &Vehicle{VehicleType: t, Groups: []int{}}
+	v.Radius = game.VehicleRadius
+	switch t {
+	case Vehicle_Tank:
+		v.MaxDurability = game.TankDurability
+		v.MaxSpeed = game.TankSpeed
+		v.VisionRange = game.TankVisionRange
+		v.GroundAttackRange = game.TankGroundAttackRange
+		v.AerialAttackRange = game.TankAerialAttackRange
+		v.GroundDamage = game.TankGroundDamage
+		v.AerialDamage = game.TankAerialDamage
+		v.GroundDefence = game.TankGroundDefence
+		v.AerialDefence = game.TankAerialDefence
+		v.AttackCooldownTicks = game.TankAttackCooldownTicks
+	case Vehicle_Ifv:
+		v.MaxDurability = game.IfvDurability
+		v.MaxSpeed = game.IfvSpeed
+		v.VisionRange = game.IfvVisionRange
+		v.GroundAttackRange = game.IfvGroundAttackRange
+		v.AerialAttackRange = game.IfvAerialAttackRange
+		v.GroundDamage = game.IfvGroundDamage
+		v.AerialDamage = game.IfvAerialDamage
+		v.GroundDefence = game.IfvGroundDefence
+		v.AerialDefence = game.IfvAerialDefence
+		v.AttackCooldownTicks = game.IfvAttackCooldownTicks
+	case Vehicle_Arrv:
+		v.MaxDurability = game.ArrvDurability
+		v.MaxSpeed = game.ArrvSpeed
+		v.VisionRange = game.ArrvVisionRange
+		v.GroundDefence = game.ArrvGroundDefence
+		v.AerialDefence = game.ArrvAerialDefence
+	case Vehicle_Helicopter:
+		v.MaxDurability = game.HelicopterDurability
+		v.MaxSpeed = game.HelicopterSpeed
+		v.VisionRange = game.HelicopterVisionRange
+		v.GroundAttackRange = game.HelicopterGroundAttackRange
+		v.AerialAttackRange = game.HelicopterAerialAttackRange
+		v.GroundDamage = game.HelicopterGroundDamage
+		v.AerialDamage = game.HelicopterAerialDamage
+		v.GroundDefence = game.HelicopterGroundDefence
+		v.AerialDefence = game.HelicopterAerialDefence
+		v.AttackCooldownTicks = game.HelicopterAttackCooldownTicks
+		v.Aerial = true
+	case Vehicle_Fighter:
+		v.MaxDurability = game.FighterDurability
+		v.MaxSpeed = game.FighterSpeed
+		v.VisionRange = game.FighterVisionRange
+		v.GroundAttackRange = game.FighterGroundAttackRange
+		v.AerialAttackRange = game.FighterAerialAttackRange
+		v.GroundDamage = game.FighterGroundDamage
+		v.AerialDamage = game.FighterAerialDamage
+		v.GroundDefence = game.FighterGroundDefence
+		v.AerialDefence = game.FighterAerialDefence
+		v.AttackCooldownTicks = game.FighterAttackCooldownTicks
+		v.Aerial = true
+	}
+	v.Durability = v.MaxDurability
+	v.SquaredVisionRange = v.VisionRange * v.VisionRange
+	v.SquaredGroundAttackRange = v.GroundAttackRange * v.GroundAttackRange
+	v.SquaredAerialAttackRange = v.AerialAttackRange * v.AerialAttackRange
+	return v
+}
+
+func (s *Simulator) controlCenterCount(playerId int64) int {
+	n := 0
+	for _, f := range s.Facilities {
+		if f.Type == Facility_Control_Center && f.OwnerPlayerId == playerId {
+			n++
+		}
+	}
+	return n
+}
+
+func (s *Simulator) ownVehicles(playerId int64) []*Vehicle {
+	var r []*Vehicle
+	for _, v := range s.Vehicles() {
+		if v.PlayerId == playerId {
+			r = append(r, v)
+		}
+	}
+	return r
+}
@@ -0,0 +1,148 @@
+package simulator
+
+import (
+	. "codewars"
+	"math"
+	"testing"
+)
+
+func tick(s *Simulator, n int, first *Move) {
+	for i := 0; i < n; i++ {
+		s.Tick([]*Move{first, nil})
+		first = nil
+	}
+}
+
+func TestDamageTakesDefenceAgainstAttackerKind(t *testing.T) {
+	g := NewGame()
+	s := New(g)
+	helicopter := s.AddVehicle(1, Vehicle_Helicopter, 100, 100)
+	tank := s.AddVehicle(2, Vehicle_Tank, 105, 100)
+	tick(s, 1, nil)
+
+	if want := g.TankDurability - (g.HelicopterGroundDamage - g.TankAerialDefence); tank.Durability != want {
+		t.Errorf("tank durability = %d, want %d", tank.Durability, want)
+	}
+	if want := g.HelicopterDurability - (g.TankAerialDamage - g.HelicopterGroundDefence); helicopter.Durability != want {
+		t.Errorf("helicopter durability = %d, want %d", helicopter.Durability, want)
+	}
+}
+
+func TestAttackCooldown(t *testing.T) {
+	g := NewGame()
+	s := New(g)
+	s.AddVehicle(1, Vehicle_Tank, 100, 100)
+	arrv := s.AddVehicle(2, Vehicle_Arrv, 105, 100)
+	perShot := g.TankGroundDamage - g.ArrvGroundDefence
+
+	tick(s, 1, nil)
+	if arrv.Durability != g.ArrvDurability-perShot {
+		t.Fatalf("after the first tick durability = %d, want %d", arrv.Durability, g.ArrvDurability-perShot)
+	}
+	tick(s, g.TankAttackCooldownTicks-1, nil)
+	if arrv.Durability != g.ArrvDurability-perShot {
+		t.Fatalf("tank fired again before its cooldown: durability = %d", arrv.Durability)
+	}
+	tick(s, 1, nil)
+	if len(s.Vehicles()) != 1 {
+		t.Fatalf("ARRV not destroyed by the second shot on tick %d", g.TankAttackCooldownTicks)
+	}
+}
+
+func TestMovement(t *testing.T) {
+	g := NewGame()
+	s := New(g)
+	terrain := make([][]TerrainType, g.TerrainWeatherMapColumnCount)
+	for i := range terrain {
+		terrain[i] = make([]TerrainType, g.TerrainWeatherMapRowCount)
+	}
+	terrain[0][0] = Terrain_Swamp
+	s.SetTerrain(terrain)
+	plain := s.AddVehicle(1, Vehicle_Tank, 100, 100)
+	swamp := s.AddVehicle(1, Vehicle_Tank, 10, 10)
+
+	tick(s, 1, new(Move).ClearAndSelect(Rect{Right: g.WorldWidth, Bottom: g.WorldHeight}, Vehicle_Unknown))
+	tick(s, 1, new(Move).MoveBy(10, 0, 0))
+	if math.Abs(plain.X-(100+g.TankSpeed)) > 1e-9 || plain.Y != 100 {
+		t.Errorf("tank on plain at (%v, %v), want (%v, 100)", plain.X, plain.Y, 100+g.TankSpeed)
+	}
+	if want := 10 + g.TankSpeed*g.SwampTerrainSpeedFactor; math.Abs(swamp.X-want) > 1e-9 {
+		t.Errorf("tank in swamp at x = %v, want %v", swamp.X, want)
+	}
+	tick(s, 100, nil)
+	if plain.X != 110 || swamp.X != 20 {
+		t.Errorf("tanks stopped at x = %v and %v, want 110 and 20", plain.X, swamp.X)
+	}
+}
+
+func TestCapture(t *testing.T) {
+	g := NewGame()
+	s := New(g)
+	f := s.AddFacility(Facility_Control_Center, 0, 0)
+	for i := 0; i < 100; i++ {
+		s.AddVehicle(1, Vehicle_Tank, 10+float64(i%10)*4, 10+float64(i/10)*4)
+	}
+	ticks := int(g.MaxFacilityCapturePoints / (100 * g.FacilityCapturePointsPerVehiclePerTick))
+
+	tick(s, ticks-1, nil)
+	if f.OwnerPlayerId != -1 {
+		t.Fatalf("captured after %d ticks, want %d", ticks-1, ticks)
+	}
+	tick(s, 1, nil)
+	if f.OwnerPlayerId != 1 {
+		t.Fatalf("owner after %d ticks = %d, want 1", ticks, f.OwnerPlayerId)
+	}
+	if s.Players[0].Score != g.FacilityCaptureScore {
+		t.Errorf("score = %d, want %d", s.Players[0].Score, g.FacilityCaptureScore)
+	}
+}
+
+func TestProduction(t *testing.T) {
+	g := NewGame()
+	s := New(g)
+	f := s.AddFacility(Facility_Vehicle_Factory, 0, 0)
+	f.OwnerPlayerId = 1
+
+	tick(s, g.TankProductionCost-1, new(Move).SetupProduction(f.Id, Vehicle_Tank))
+	if n := len(s.Vehicles()); n != 0 {
+		t.Fatalf("%d vehicles produced before the cost was paid", n)
+	}
+	tick(s, 1, nil)
+	vehicles := s.Vehicles()
+	if len(vehicles) != 1 || vehicles[0].VehicleType != Vehicle_Tank || vehicles[0].PlayerId != 1 {
+		t.Fatalf("produced %v, want one tank of player 1", vehicles)
+	}
+	if !f.Contains(vehicles[0].X, vehicles[0].Y) {
+		t.Errorf("tank produced at (%v, %v), off the factory", vehicles[0].X, vehicles[0].Y)
+	}
+}
+
+func TestActionCooldown(t *testing.T) {
+	g := NewGame()
+	s := New(g)
+	v := s.AddVehicle(1, Vehicle_Tank, 100, 100)
+	p := s.Players[0]
+	all := Rect{Right: g.WorldWidth, Bottom: g.WorldHeight}
+
+	for i := 0; i < g.BaseActionCount; i++ {
+		if p.RemainingActionCooldownTicks != 0 {
+			t.Fatalf("cooldown %d before action %d", p.RemainingActionCooldownTicks, i+1)
+		}
+		tick(s, 1, new(Move).Deselect(all, Vehicle_Unknown))
+	}
+	if want := g.ActionDetectionInterval - g.BaseActionCount; p.RemainingActionCooldownTicks != want {
+		t.Fatalf("cooldown = %d, want %d", p.RemainingActionCooldownTicks, want)
+	}
+	tick(s, 1, new(Move).ClearAndSelect(all, Vehicle_Unknown))
+	if v.Selected {
+		t.Error("action made on cooldown was applied")
+	}
+	tick(s, g.ActionDetectionInterval-g.BaseActionCount-1, nil)
+	if p.RemainingActionCooldownTicks != 0 {
+		t.Fatalf("cooldown = %d after the detection interval", p.RemainingActionCooldownTicks)
+	}
+	tick(s, 1, new(Move).ClearAndSelect(all, Vehicle_Unknown))
+	if !v.Selected {
+		t.Error("action after the cooldown was not applied")
+	}
+}
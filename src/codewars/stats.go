@@ -0,0 +1,162 @@
+package codewars
+
+// VehicleTypes lists the vehicle types in the order of their wire codes.
+var VehicleTypes = []VehicleType{Vehicle_Arrv, Vehicle_Fighter, Vehicle_Helicopter, Vehicle_Ifv, Vehicle_Tank}
+
+// VehicleStats gathers the properties of one vehicle type from the flat
+// fields of Game. ARRVs do not attack: their ranges, damage and cooldown are
+// zero.
+type VehicleStats struct {
+	Type                VehicleType
+	Aerial              bool
+	Durability          int
+	Speed               float64
+	VisionRange         float64
+	GroundAttackRange   float64
+	AerialAttackRange   float64
+	GroundDamage        int
+	AerialDamage        int
+	GroundDefence       int
+	AerialDefence       int
+	AttackCooldownTicks int
+	ProductionCost      int
+}
+
+// Stats returns the properties of vehicles of type t, only Type set for an
+// unknown t.
+func (g *Game) Stats(t VehicleType) VehicleStats {
+	switch t {
+	case Vehicle_Arrv:
+		return VehicleStats{
+			Type:           t,
+			Durability:     g.ArrvDurability,
+			Speed:          g.ArrvSpeed,
+			VisionRange:    g.ArrvVisionRange,
+			GroundDefence:  g.ArrvGroundDefence,
+			AerialDefence:  g.ArrvAerialDefence,
+			ProductionCost: g.ArrvProductionCost,
+		}
+	case Vehicle_Fighter:
+		return VehicleStats{
+			Type:                t,
+			Aerial:              true,
+			Durability:          g.FighterDurability,
+			Speed:               g.FighterSpeed,
+			VisionRange:         g.FighterVisionRange,
+			GroundAttackRange:   g.FighterGroundAttackRange,
+			AerialAttackRange:   g.FighterAerialAttackRange,
+			GroundDamage:        g.FighterGroundDamage,
+			AerialDamage:        g.FighterAerialDamage,
+			GroundDefence:       g.FighterGroundDefence,
+			AerialDefence:       g.FighterAerialDefence,
+			AttackCooldownTicks: g.FighterAttackCooldownTicks,
+			ProductionCost:      g.FighterProductionCost,
+		}
+	case Vehicle_Helicopter:
+		return VehicleStats{
+			Type:                t,
+			Aerial:              true,
+			Durability:          g.HelicopterDurability,
+			Speed:               g.HelicopterSpeed,
+			VisionRange:         g.HelicopterVisionRange,
+			GroundAttackRange:   g.HelicopterGroundAttackRange,
+			AerialAttackRange:   g.HelicopterAerialAttackRange,
+			GroundDamage:        g.HelicopterGroundDamage,
+			AerialDamage:        g.HelicopterAerialDamage,
+			GroundDefence:       g.HelicopterGroundDefence,
+			AerialDefence:       g.HelicopterAerialDefence,
+			AttackCooldownTicks: g.HelicopterAttackCooldownTicks,
+			ProductionCost:      g.HelicopterProductionCost,
+		}
+	case Vehicle_Ifv:
+		return VehicleStats{
+			Type:                t,
+			Durability:          g.IfvDurability,
+			Speed:               g.IfvSpeed,
+			VisionRange:         g.IfvVisionRange,
+			GroundAttackRange:   g.IfvGroundAttackRange,
+			AerialAttackRange:   g.IfvAerialAttackRange,
+			GroundDamage:        g.IfvGroundDamage,
+			AerialDamage:        g.IfvAerialDamage,
+			GroundDefence:       g.IfvGroundDefence,
+			AerialDefence:       g.IfvAerialDefence,
+			AttackCooldownTicks: g.IfvAttackCooldownTicks,
+			ProductionCost:      g.IfvProductionCost,
+		}
+	case Vehicle_Tank:
+		return VehicleStats{
+			Type:                t,
+			Durability:          g.TankDurability,
+			Speed:               g.TankSpeed,
+			VisionRange:         g.TankVisionRange,
+			GroundAttackRange:   g.TankGroundAttackRange,
+			AerialAttackRange:   g.TankAerialAttackRange,
+			GroundDamage:        g.TankGroundDamage,
+			AerialDamage:        g.TankAerialDamage,
+			GroundDefence:       g.TankGroundDefence,
+			AerialDefence:       g.TankAerialDefence,
+			AttackCooldownTicks: g.TankAttackCooldownTicks,
+			ProductionCost:      g.TankProductionCost,
+		}
+	}
+	return VehicleStats{Type: t}
+}
+
+// AttackRange returns the range at which s hits an aerial or ground target.
+func (s VehicleStats) AttackRange(aerialTarget bool) float64 {
+	if aerialTarget {
+		return s.AerialAttackRange
+	}
+	return s.GroundAttackRange
+}
+
+// Damage returns the damage s deals to an aerial or ground target before its
+// defence.
+func (s VehicleStats) Damage(aerialTarget bool) int {
+	if aerialTarget {
+		return s.AerialDamage
+	}
+	return s.GroundDamage
+}
+
+// Defence returns what s takes off the damage of an aerial or ground attacker.
+func (s VehicleStats) Defence(aerialAttacker bool) int {
+	if aerialAttacker {
+		return s.AerialDefence
+	}
+	return s.GroundDefence
+}
+
+// EffectiveDamage returns what one shot of s takes from the durability of a
+// target, zero if the target's defence absorbs it all.
+func (s VehicleStats) EffectiveDamage(target VehicleStats) int {
+	d := s.Damage(target.Aerial) - target.Defence(s.Aerial)
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+// DamageMatrix holds the EffectiveDamage of every attacker type, first
+// index, against every target type, second index, both by wire code.
+type DamageMatrix [5][5]int
+
+// NewDamageMatrix computes the matrix once for the rules of game.
+func NewDamageMatrix(game *Game) *DamageMatrix {
+	m := &DamageMatrix{}
+	for _, a := range VehicleTypes {
+		for _, t := range VehicleTypes {
+			m[a][t] = game.Stats(a).EffectiveDamage(game.Stats(t))
+		}
+	}
+	return m
+}
+
+// Damage returns what one shot of attacker takes from target, zero for
+// unknown types.
+func (m *DamageMatrix) Damage(attacker, target VehicleType) int {
+	if attacker < 0 || int(attacker) >= len(m) || target < 0 || int(target) >= len(m) {
+		return 0
+	}
+	return m[attacker][target]
+}
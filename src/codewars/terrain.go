@@ -3,7 +3,60 @@ package codewars
 type TerrainType int
 
 const (
-	Terrain_Plain TerrainType = iota
-	Terrain_Swamp
-	Terrain_Forest
+	Terrain_Plain  TerrainType = 0
+	Terrain_Swamp  TerrainType = 1
+	Terrain_Forest TerrainType = 2
 )
+
+var terrainTypeInfo = enumInfo{"TerrainType", []string{"Plain", "Swamp", "Forest"}, ""}
+
+func (t TerrainType) String() string { return terrainTypeInfo.text(int(t)) }
+
+func (t TerrainType) MarshalText() ([]byte, error) { return terrainTypeInfo.marshal(int(t)) }
+
+func (t *TerrainType) UnmarshalText(text []byte) error {
+	v, err := terrainTypeInfo.unmarshal(text)
+	if err != nil {
+		return err
+	}
+	*t = TerrainType(v)
+	return nil
+}
+
+// Encode returns the wire code of t.
+func (t TerrainType) Encode() (byte, error) { return terrainTypeInfo.encode(int(t)) }
+
+func DecodeTerrainType(b byte) (TerrainType, error) {
+	v, err := terrainTypeInfo.decode(b)
+	return TerrainType(v), err
+}
+
+func (g *Game) TerrainSpeedFactor(t TerrainType) float64 {
+	switch t {
+	case Terrain_Swamp:
+		return g.SwampTerrainSpeedFactor
+	case Terrain_Forest:
+		return g.ForestTerrainSpeedFactor
+	}
+	return g.PlainTerrainSpeedFactor
+}
+
+func (g *Game) TerrainVisionFactor(t TerrainType) float64 {
+	switch t {
+	case Terrain_Swamp:
+		return g.SwampTerrainVisionFactor
+	case Terrain_Forest:
+		return g.ForestTerrainVisionFactor
+	}
+	return g.PlainTerrainVisionFactor
+}
+
+func (g *Game) TerrainStealthFactor(t TerrainType) float64 {
+	switch t {
+	case Terrain_Swamp:
+		return g.SwampTerrainStealthFactor
+	case Terrain_Forest:
+		return g.ForestTerrainStealthFactor
+	}
+	return g.PlainTerrainStealthFactor
+}
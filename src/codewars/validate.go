@@ -0,0 +1,106 @@
+package codewars
+
+import (
+	"fmt"
+	"math"
+)
+
+// MoveError describes why the server would ignore a move.
+type MoveError struct {
+	Action ActionType
+	Reason string
+}
+
+func (e *MoveError) Error() string {
+	return fmt.Sprintf("codewars: invalid %v move: %s", e.Action, e.Reason)
+}
+
+// Validate checks m against the rules of game and the state of world as seen
+// by me, and returns a *MoveError for the first problem found. Action_None is
+// always valid.
+func (m *Move) Validate(me *Player, world *World, game *Game) error {
+	if m.Action == Action_None {
+		return nil
+	}
+	fail := func(format string, args ...interface{}) error {
+		return &MoveError{m.Action, fmt.Sprintf(format, args...)}
+	}
+	if _, err := m.Action.Encode(); err != nil {
+		return fail("unknown action")
+	}
+	if me.RemainingActionCooldownTicks > 0 {
+		return fail("actions are on cooldown for %d more ticks", me.RemainingActionCooldownTicks)
+	}
+	for _, f := range []struct {
+		name  string
+		value float64
+	}{
+		{"Left", m.Left}, {"Top", m.Top}, {"Right", m.Right}, {"Bottom", m.Bottom},
+		{"X", m.X}, {"Y", m.Y}, {"Angle", m.Angle}, {"Factor", m.Factor},
+		{"Max_speed", m.Max_speed}, {"Max_angular_speed", m.Max_angular_speed},
+	} {
+		if math.IsNaN(f.value) || math.IsInf(f.value, 0) {
+			return fail("%s is %v", f.name, f.value)
+		}
+	}
+	if _, err := m.Vehicle_type.Encode(); err != nil {
+		return fail("unknown vehicle type %d", int(m.Vehicle_type))
+	}
+
+	switch m.Action {
+	case Action_Clear_And_Select, Action_Add_To_Selection, Action_Deselect:
+		if m.Group < 0 || m.Group > game.MaxUnitGroup {
+			return fail("group %d is not in 0..%d", m.Group, game.MaxUnitGroup)
+		}
+		if m.Group == 0 && (m.Left > m.Right || m.Top > m.Bottom) {
+			return fail("empty rectangle [%v, %v]x[%v, %v]", m.Left, m.Right, m.Top, m.Bottom)
+		}
+	case Action_Assign, Action_Dismiss, Action_Disband:
+		if m.Group < 1 || m.Group > game.MaxUnitGroup {
+			return fail("group %d is not in 1..%d", m.Group, game.MaxUnitGroup)
+		}
+	case Action_Move:
+		if m.Max_speed < 0 {
+			return fail("negative Max_speed")
+		}
+	case Action_Rotate:
+		if m.Max_speed < 0 || m.Max_angular_speed < 0 {
+			return fail("negative Max_speed or Max_angular_speed")
+		}
+	case Action_Scale:
+		if m.Factor <= 0 {
+			return fail("Factor %v is not positive", m.Factor)
+		}
+		if m.Max_speed < 0 {
+			return fail("negative Max_speed")
+		}
+	case Action_Setup_Vehicle_Production:
+		var facility *Facility
+		for _, f := range world.Facilities {
+			if f != nil && f.Id == m.Facility_id {
+				facility = f
+			}
+		}
+		switch {
+		case facility == nil:
+			return fail("no facility %d", m.Facility_id)
+		case facility.Type != Facility_Vehicle_Factory:
+			return fail("facility %d is a %v", facility.Id, facility.Type)
+		case facility.OwnerPlayerId != me.Id:
+			return fail("facility %d is not ours", facility.Id)
+		}
+	case Action_Tactical_Nuclear_Strike:
+		if me.RemainingNuclearStrikeCooldownTicks > 0 {
+			return fail("nuclear strike is on cooldown for %d more ticks", me.RemainingNuclearStrikeCooldownTicks)
+		}
+		v := world.GetVehicleById(m.Vehicle_id)
+		if v == nil || v.PlayerId != me.Id {
+			return fail("no vehicle %d of ours", m.Vehicle_id)
+		}
+		vision := v.VisionRange * world.VisionFactorAt(game, v.X, v.Y, v.Aerial)
+		if v.GetDistanceTo(m.X, m.Y) > vision {
+			return fail("target is out of the vision range of vehicle %d", v.Id)
+		}
+	}
+	return nil
+}
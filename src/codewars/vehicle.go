@@ -7,14 +7,37 @@ import (
 type VehicleType int
 
 const (
-	Vehicle_Unknown VehicleType = -1
-	Vehicle_Arrv    VehicleType = iota
-	Vehicle_Fighter
-	Vehicle_Helicopter
-	Vehicle_Ifv
-	Vehicle_Tank
+	Vehicle_Unknown    VehicleType = -1
+	Vehicle_Arrv       VehicleType = 0
+	Vehicle_Fighter    VehicleType = 1
+	Vehicle_Helicopter VehicleType = 2
+	Vehicle_Ifv        VehicleType = 3
+	Vehicle_Tank       VehicleType = 4
 )
 
+var vehicleTypeInfo = enumInfo{"VehicleType", []string{"Arrv", "Fighter", "Helicopter", "Ifv", "Tank"}, "Unknown"}
+
+func (t VehicleType) String() string { return vehicleTypeInfo.text(int(t)) }
+
+func (t VehicleType) MarshalText() ([]byte, error) { return vehicleTypeInfo.marshal(int(t)) }
+
+func (t *VehicleType) UnmarshalText(text []byte) error {
+	v, err := vehicleTypeInfo.unmarshal(text)
+	if err != nil {
+		return err
+	}
+	*t = VehicleType(v)
+	return nil
+}
+
+// Encode returns the wire code of t.
+func (t VehicleType) Encode() (byte, error) { return vehicleTypeInfo.encode(int(t)) }
+
+func DecodeVehicleType(b byte) (VehicleType, error) {
+	v, err := vehicleTypeInfo.decode(b)
+	return VehicleType(v), err
+}
+
 type Vehicle struct {
 	CircularUnit
 	PlayerId                     int64
@@ -53,3 +76,29 @@ func (v *Vehicle) update(vehicle_update *VehicleUpdate) {
 	v.Selected = vehicle_update.Selected
 	v.Groups = vehicle_update.Groups
 }
+
+// UpdateVehicles adds newVehicles to vehicleById, applies updates to the
+// vehicles already there and removes those whose durability dropped to zero
+// (destroyed or, with fog of war, out of sight). Updates for unknown vehicles
+// are ignored.
+func UpdateVehicles(vehicleById map[int64]*Vehicle, newVehicles []*Vehicle, updates []*VehicleUpdate) {
+	for _, v := range newVehicles {
+		if v != nil {
+			vehicleById[v.Id] = v
+		}
+	}
+	for _, u := range updates {
+		if u == nil {
+			continue
+		}
+		v, ok := vehicleById[u.Id]
+		if !ok {
+			continue
+		}
+		if u.Durability <= 0 {
+			delete(vehicleById, u.Id)
+			continue
+		}
+		v.update(u)
+	}
+}
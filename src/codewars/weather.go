@@ -3,7 +3,60 @@ package codewars
 type WeatherType int
 
 const (
-	Weather_Clear WeatherType = iota
-	Weather_Cloud
-	Weather_Rain
+	Weather_Clear WeatherType = 0
+	Weather_Cloud WeatherType = 1
+	Weather_Rain  WeatherType = 2
 )
+
+var weatherTypeInfo = enumInfo{"WeatherType", []string{"Clear", "Cloud", "Rain"}, ""}
+
+func (t WeatherType) String() string { return weatherTypeInfo.text(int(t)) }
+
+func (t WeatherType) MarshalText() ([]byte, error) { return weatherTypeInfo.marshal(int(t)) }
+
+func (t *WeatherType) UnmarshalText(text []byte) error {
+	v, err := weatherTypeInfo.unmarshal(text)
+	if err != nil {
+		return err
+	}
+	*t = WeatherType(v)
+	return nil
+}
+
+// Encode returns the wire code of t.
+func (t WeatherType) Encode() (byte, error) { return weatherTypeInfo.encode(int(t)) }
+
+func DecodeWeatherType(b byte) (WeatherType, error) {
+	v, err := weatherTypeInfo.decode(b)
+	return WeatherType(v), err
+}
+
+func (g *Game) WeatherSpeedFactor(t WeatherType) float64 {
+	switch t {
+	case Weather_Cloud:
+		return g.CloudWeatherSpeedFactor
+	case Weather_Rain:
+		return g.RainWeatherSpeedFactor
+	}
+	return g.ClearWeatherSpeedFactor
+}
+
+func (g *Game) WeatherVisionFactor(t WeatherType) float64 {
+	switch t {
+	case Weather_Cloud:
+		return g.CloudWeatherVisionFactor
+	case Weather_Rain:
+		return g.RainWeatherVisionFactor
+	}
+	return g.ClearWeatherVisionFactor
+}
+
+func (g *Game) WeatherStealthFactor(t WeatherType) float64 {
+	switch t {
+	case Weather_Cloud:
+		return g.CloudWeatherStealthFactor
+	case Weather_Rain:
+		return g.RainWeatherStealthFactor
+	}
+	return g.ClearWeatherStealthFactor
+}
@@ -1,5 +1,9 @@
 package codewars
 
+import (
+	"sort"
+)
+
 type World struct {
 	TickIndex       int
 	TickCount       int
@@ -10,6 +14,11 @@ type World struct {
 	TerrainByCellXY [][]TerrainType
 	WeatherByCellXY [][]WeatherType
 	Facilities      []*Facility
+
+	// VehicleById holds every vehicle known to the player, folded across
+	// ticks from NewVehicles and VehicleUpdate. The runner reuses the same map
+	// and the same *Vehicle values from tick to tick.
+	VehicleById map[int64]*Vehicle
 }
 
 func (w *World) GetMyPlayer() *Player {
@@ -29,3 +38,111 @@ func (w *World) GetOpponentPlayer() *Player {
 	}
 	return nil
 }
+
+func (w *World) GetVehicleById(id int64) *Vehicle {
+	return w.VehicleById[id]
+}
+
+// GetVehicles returns all known vehicles ordered by id.
+func (w *World) GetVehicles() []*Vehicle {
+	return w.filterVehicles(func(*Vehicle) bool { return true })
+}
+
+func (w *World) GetMyVehicles() []*Vehicle {
+	me := w.GetMyPlayer()
+	if me == nil {
+		return nil
+	}
+	return w.filterVehicles(func(v *Vehicle) bool { return v.PlayerId == me.Id })
+}
+
+func (w *World) GetOpponentVehicles() []*Vehicle {
+	me := w.GetMyPlayer()
+	if me == nil {
+		return nil
+	}
+	return w.filterVehicles(func(v *Vehicle) bool { return v.PlayerId != me.Id })
+}
+
+func (w *World) filterVehicles(keep func(*Vehicle) bool) []*Vehicle {
+	r := make([]*Vehicle, 0, len(w.VehicleById))
+	for _, v := range w.VehicleById {
+		if keep(v) {
+			r = append(r, v)
+		}
+	}
+	sort.Slice(r, func(i, j int) bool { return r[i].Id < r[j].Id })
+	return r
+}
+
+// TerrainAt returns the terrain of the cell containing (x, y). The map has
+// TerrainWeatherMapColumnCount columns and TerrainWeatherMapRowCount rows of
+// game evenly covering the Width and Height of the world; points outside of
+// it are clamped to the nearest border cell. Where the map has no cell it
+// returns Terrain_Plain.
+func (w *World) TerrainAt(game *Game, x, y float64) TerrainType {
+	i, j := w.cell(game, x, y)
+	if i < 0 || i >= len(w.TerrainByCellXY) || j < 0 || j >= len(w.TerrainByCellXY[i]) {
+		return Terrain_Plain
+	}
+	return w.TerrainByCellXY[i][j]
+}
+
+// WeatherAt returns the weather of the cell containing (x, y), see
+// TerrainAt. Where the map has no cell it returns Weather_Clear.
+func (w *World) WeatherAt(game *Game, x, y float64) WeatherType {
+	i, j := w.cell(game, x, y)
+	if i < 0 || i >= len(w.WeatherByCellXY) || j < 0 || j >= len(w.WeatherByCellXY[i]) {
+		return Weather_Clear
+	}
+	return w.WeatherByCellXY[i][j]
+}
+
+// SpeedFactorAt returns the speed factor for a vehicle at (x, y): ground
+// vehicles are affected by terrain, aerial ones by weather.
+func (w *World) SpeedFactorAt(game *Game, x, y float64, aerial bool) float64 {
+	if aerial {
+		return game.WeatherSpeedFactor(w.WeatherAt(game, x, y))
+	}
+	return game.TerrainSpeedFactor(w.TerrainAt(game, x, y))
+}
+
+// VisionFactorAt returns the factor applied to the vision range of a vehicle
+// standing at (x, y).
+func (w *World) VisionFactorAt(game *Game, x, y float64, aerial bool) float64 {
+	if aerial {
+		return game.WeatherVisionFactor(w.WeatherAt(game, x, y))
+	}
+	return game.TerrainVisionFactor(w.TerrainAt(game, x, y))
+}
+
+// StealthFactorAt returns the factor applied to the range at which a vehicle
+// standing at (x, y) can be seen.
+func (w *World) StealthFactorAt(game *Game, x, y float64, aerial bool) float64 {
+	if aerial {
+		return game.WeatherStealthFactor(w.WeatherAt(game, x, y))
+	}
+	return game.TerrainStealthFactor(w.TerrainAt(game, x, y))
+}
+
+// cell returns the column and the row of the terrain and weather maps
+// containing (x, y).
+func (w *World) cell(game *Game, x, y float64) (int, int) {
+	return cellIndex(x, w.Width, game.TerrainWeatherMapColumnCount), cellIndex(y, w.Height, game.TerrainWeatherMapRowCount)
+}
+
+// cellIndex returns which of count equal cells across size holds v, clamped
+// to the first and the last.
+func cellIndex(v, size float64, count int) int {
+	i := 0
+	if size > 0 {
+		i = int(v / size * float64(count))
+	}
+	if i < 0 {
+		return 0
+	}
+	if i >= count {
+		return count - 1
+	}
+	return i
+}
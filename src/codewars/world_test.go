@@ -0,0 +1,36 @@
+package codewars
+
+import (
+	"testing"
+)
+
+func TestTerrainAndWeatherAt(t *testing.T) {
+	g := &Game{TerrainWeatherMapColumnCount: 2, TerrainWeatherMapRowCount: 4}
+	w := &World{
+		Width:           1024,
+		Height:          1024,
+		TerrainByCellXY: [][]TerrainType{{Terrain_Plain, Terrain_Swamp, Terrain_Forest, Terrain_Plain}, {Terrain_Forest}},
+		WeatherByCellXY: [][]WeatherType{{Weather_Cloud, Weather_Rain, Weather_Clear, Weather_Cloud}},
+	}
+	for _, c := range []struct {
+		x, y    float64
+		terrain TerrainType
+		weather WeatherType
+	}{
+		{0, 0, Terrain_Plain, Weather_Cloud},
+		{511, 256, Terrain_Swamp, Weather_Rain},
+		{100, 600, Terrain_Forest, Weather_Clear},
+		// clamped to the border cells
+		{-50, 5000, Terrain_Plain, Weather_Cloud},
+		{2000, -1, Terrain_Forest, Weather_Clear},
+		// cells missing from the maps
+		{600, 300, Terrain_Plain, Weather_Clear},
+	} {
+		if got := w.TerrainAt(g, c.x, c.y); got != c.terrain {
+			t.Errorf("TerrainAt(%v, %v) = %v, want %v", c.x, c.y, got, c.terrain)
+		}
+		if got := w.WeatherAt(g, c.x, c.y); got != c.weather {
+			t.Errorf("WeatherAt(%v, %v) = %v, want %v", c.x, c.y, got, c.weather)
+		}
+	}
+}
@@ -2,7 +2,6 @@ package main
 
 import (
 	. "codewars"
-	"fmt"
 )
 
 type MyStrategy struct{}
@@ -14,15 +13,11 @@ func New() Strategy {
 func (s *MyStrategy) Move(me *Player, world *World, game *Game, move *Move) {
 	// put your code here
 	if world.TickIndex == 0 {
-		move.Action = Action_Clear_And_Select
-		move.Right = world.Width
-		move.Bottom = world.Height
+		move.ClearAndSelect(Rect{Right: world.Width, Bottom: world.Height}, Vehicle_Unknown)
 	}
 
 	if world.TickIndex == 1 {
-		move.Action = Action_Move
-		move.X = world.Width / 2.0
-		move.Y = world.Height / 2.0
+		move.MoveBy(world.Width/2.0, world.Height/2.0, 0)
 	}
 
 }